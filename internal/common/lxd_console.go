@@ -0,0 +1,42 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	lxd "github.com/canonical/lxd/client"
+)
+
+// InstanceConsoleLog retrieves the console log of an instance.
+func InstanceConsoleLog(server lxd.InstanceServer, instanceName string) (string, error) {
+	reader, err := server.GetInstanceConsoleLog(instanceName, &lxd.InstanceConsoleLogArgs{})
+	if err != nil {
+		return "", err
+	}
+
+	defer reader.Close()
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("Failed to read console log of instance %q: %v", instanceName, err)
+	}
+
+	return string(content), nil
+}
+
+// TailLines returns the last n lines of the given text. Trailing newlines
+// are ignored. If n is not positive, the whole text is returned.
+func TailLines(text string, n int) string {
+	text = strings.TrimRight(text, "\r\n")
+	if n <= 0 || text == "" {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,25 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTailLines(t *testing.T) {
+	tests := []struct {
+		Text  string
+		Lines int
+		Tail  string
+	}{
+		{Text: "", Lines: 2, Tail: ""},
+		{Text: "a\nb\nc\n", Lines: 0, Tail: "a\nb\nc"},
+		{Text: "a\nb\nc\n", Lines: 2, Tail: "b\nc"},
+		{Text: "a\nb\nc", Lines: 5, Tail: "a\nb\nc"},
+		{Text: "a\r\nb\r\n\r\n", Lines: 1, Tail: "b"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.Tail, TailLines(test.Text, test.Lines), "Text %q, lines %d", test.Text, test.Lines)
+	}
+}
@@ -0,0 +1,286 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	lxd "github.com/canonical/lxd/client"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/mitchellh/go-homedir"
+
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/utils"
+)
+
+// LocalDirectoryFiles walks the local directory and returns a map of regular
+// files, where keys are slash-separated paths relative to the source directory
+// and values are SHA-256 checksums of the file contents.
+//
+// Files are included if they match any of the include patterns (or if no
+// include patterns are given) and do not match any of the exclude patterns.
+// Directories matching an exclude pattern are skipped entirely. Symbolic links
+// and other non-regular files are ignored.
+func LocalDirectoryFiles(sourcePath string, include []string, exclude []string) (map[string]string, error) {
+	sourcePath, err := homedir.Expand(sourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to determine source directory path: %v", err)
+	}
+
+	info, err := os.Stat(sourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read source directory: %v", err)
+	}
+
+	if !info.IsDir() {
+		return nil, fmt.Errorf("Source path %q is not a directory", sourcePath)
+	}
+
+	files := make(map[string]string)
+
+	err = filepath.WalkDir(sourcePath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(sourcePath, p)
+		if err != nil {
+			return err
+		}
+
+		if rel == "." {
+			return nil
+		}
+
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if MatchesAnyGlob(rel, exclude) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		if MatchesAnyGlob(rel, exclude) {
+			return nil
+		}
+
+		if len(include) > 0 && !MatchesAnyGlob(rel, include) {
+			return nil
+		}
+
+		sum, err := LocalFileSHA256(p)
+		if err != nil {
+			return err
+		}
+
+		files[rel] = sum
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Unable to read source directory %q: %v", sourcePath, err)
+	}
+
+	return files, nil
+}
+
+// MatchesAnyGlob determines whether the slash-separated relative path matches
+// any of the given glob patterns. Patterns without a slash are also matched
+// against the base name of the path, so "*.tmp" matches "a/b/c.tmp".
+func MatchesAnyGlob(relPath string, patterns []string) bool {
+	for _, pattern := range patterns {
+		ok, _ := path.Match(pattern, relPath)
+		if ok {
+			return true
+		}
+
+		if !strings.Contains(pattern, "/") {
+			ok, _ = path.Match(pattern, path.Base(relPath))
+			if ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ValidateGlob returns an error if the glob pattern is malformed.
+func ValidateGlob(pattern string) error {
+	_, err := path.Match(pattern, "")
+	if err != nil {
+		return fmt.Errorf("Invalid glob pattern %q: %v", pattern, err)
+	}
+
+	return nil
+}
+
+// InstanceDirectoryChecksums returns SHA-256 checksums of the given files
+// within the target directory of an instance. Files are given as slash-separated
+// paths relative to the target directory. Missing files and paths that
+// are not regular files are omitted from the result.
+//
+// Files are read over a single SFTP connection, however, the content of
+// each file is still transferred to compute its checksum.
+func InstanceDirectoryChecksums(server lxd.InstanceServer, instanceName string, targetDir string, files []string) (map[string]string, error) {
+	sftpConn, err := server.GetInstanceFileSFTP(instanceName)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = sftpConn.Close() }()
+
+	checksums := make(map[string]string, len(files))
+
+	for _, rel := range files {
+		targetPath := path.Join(targetDir, rel)
+
+		info, err := sftpConn.Lstat(targetPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+
+			return nil, fmt.Errorf("Failed to retrieve file %q: %v", targetPath, err)
+		}
+
+		if !info.Mode().IsRegular() {
+			continue
+		}
+
+		f, err := sftpConn.Open(targetPath)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to retrieve file %q: %v", targetPath, err)
+		}
+
+		sum, err := ReaderSHA256(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read file %q: %v", targetPath, err)
+		}
+
+		checksums[rel] = sum
+	}
+
+	return checksums, nil
+}
+
+// InstanceDirectoryUpload uploads the given files from the local source
+// directory into the target directory of an instance. Missing parent
+// directories are created with the given directory mode. If file mode
+// is empty, permissions of the local files are preserved.
+func InstanceDirectoryUpload(server lxd.InstanceServer, instanceName string, sourceDir string, targetDir string, files []string, uid int64, gid int64, fileMode string, dirMode string) error {
+	sourceDir, err := homedir.Expand(sourceDir)
+	if err != nil {
+		return fmt.Errorf("Unable to determine source directory path: %v", err)
+	}
+
+	mode, err := strconv.ParseInt(dirMode, 8, 32)
+	if err != nil {
+		return fmt.Errorf("Failed to parse directory mode: %v", err)
+	}
+
+	dirArgs := lxd.InstanceFileArgs{
+		Type: "directory",
+		Mode: int(mode),
+		UID:  uid,
+		GID:  gid,
+	}
+
+	createdDirs := make(map[string]bool)
+
+	for _, rel := range files {
+		sourcePath := filepath.Join(sourceDir, filepath.FromSlash(rel))
+		targetPath := path.Join(targetDir, rel)
+
+		// Ensure parent directories exist.
+		parentDir := path.Dir(targetPath)
+		if !createdDirs[parentDir] {
+			err := recursiveMkdir(server, instanceName, parentDir, dirArgs)
+			if err != nil {
+				return fmt.Errorf("Could not create directory %q: %v", parentDir, err)
+			}
+
+			createdDirs[parentDir] = true
+		}
+
+		// Preserve local file permissions, unless file mode is set.
+		perm := fileMode
+		if perm == "" {
+			info, err := os.Stat(sourcePath)
+			if err != nil {
+				return fmt.Errorf("Unable to read source file: %v", err)
+			}
+
+			perm = fmt.Sprintf("%04o", info.Mode().Perm())
+		}
+
+		file := InstanceFileModel{
+			SourcePath: types.StringValue(sourcePath),
+			TargetPath: types.StringValue(targetPath),
+			UserID:     types.Int64Value(uid),
+			GroupID:    types.Int64Value(gid),
+			Mode:       types.StringValue(perm),
+		}
+
+		err := InstanceFileUpload(server, instanceName, file)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// InstanceDirectoryDelete deletes the given files from the target directory
+// of an instance. Subdirectories of the target directory that are left empty
+// are removed as well.
+func InstanceDirectoryDelete(ctx context.Context, server lxd.InstanceServer, instanceName string, targetDir string, files []string) error {
+	sftpConn, err := server.GetInstanceFileSFTP(instanceName)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = sftpConn.Close() }()
+
+	dirs := make(map[string]bool)
+
+	for _, rel := range files {
+		targetPath := path.Join(targetDir, rel)
+
+		err := deleteInstanceFile(ctx, server, sftpConn, instanceName, targetPath)
+		if err != nil {
+			return fmt.Errorf("Could not delete file %q: %v", targetPath, err)
+		}
+
+		// Collect parent directories within the target directory.
+		for dir := path.Dir(rel); dir != "."; dir = path.Dir(dir) {
+			dirs[path.Join(targetDir, dir)] = true
+		}
+	}
+
+	// Remove empty directories, starting from the deepest one.
+	dirPaths := utils.SortMapKeys(dirs)
+	sort.Sort(sort.Reverse(sort.StringSlice(dirPaths)))
+
+	for _, dir := range dirPaths {
+		entries, err := sftpConn.ReadDir(dir)
+		if err != nil || len(entries) > 0 {
+			continue
+		}
+
+		_ = server.DeleteInstanceFile(instanceName, dir)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/utils"
+)
+
+func TestMatchesAnyGlob(t *testing.T) {
+	tests := []struct {
+		Path     string
+		Patterns []string
+		Match    bool
+	}{
+		{Path: "a.txt", Patterns: nil, Match: false},
+		{Path: "a.txt", Patterns: []string{"*.txt"}, Match: true},
+		{Path: "dir/a.txt", Patterns: []string{"*.txt"}, Match: true},
+		{Path: "dir/a.txt", Patterns: []string{"dir/*.txt"}, Match: true},
+		{Path: "dir/sub/a.txt", Patterns: []string{"dir/*.txt"}, Match: false},
+		{Path: "dir/a.txt", Patterns: []string{"*.conf", "dir"}, Match: false},
+		{Path: "dir", Patterns: []string{"*.conf", "dir"}, Match: true},
+		{Path: "dir/.git", Patterns: []string{".git"}, Match: true},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.Match, MatchesAnyGlob(test.Path, test.Patterns), "Path %q, patterns %v", test.Path, test.Patterns)
+	}
+}
+
+func TestLocalDirectoryFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"a.txt":            "a",
+		"b.conf":           "b",
+		"sub/c.txt":        "c",
+		"sub/d.tmp":        "d",
+		"skip/e.txt":       "e",
+		"sub/deep/f.conf":  "f",
+		"sub/deep/g.txt":   "g",
+		"sub/deep/.hidden": "h",
+	}
+
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0644))
+	}
+
+	// Symbolic links are ignored.
+	require.NoError(t, os.Symlink(filepath.Join(dir, "a.txt"), filepath.Join(dir, "link.txt")))
+
+	// SHA-256 checksum of "a".
+	sumA := "ca978112ca1bbdcafac231b39a23dc4da786eff8147c4e72b9807785afee48bb"
+
+	result, err := LocalDirectoryFiles(dir, nil, nil)
+	require.NoError(t, err)
+	assert.Len(t, result, len(files))
+	assert.Equal(t, sumA, result["a.txt"])
+
+	result, err = LocalDirectoryFiles(dir, []string{"*.txt"}, []string{"skip", "*.tmp"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a.txt", "sub/c.txt", "sub/deep/g.txt"}, utils.SortMapKeys(result))
+
+	result, err = LocalDirectoryFiles(dir, nil, []string{"sub/deep", ".*"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a.txt", "b.conf", "skip/e.txt", "sub/c.txt", "sub/d.tmp"}, utils.SortMapKeys(result))
+
+	_, err = LocalDirectoryFiles(filepath.Join(dir, "a.txt"), nil, nil)
+	assert.ErrorContains(t, err, "is not a directory")
+
+	_, err = LocalDirectoryFiles(filepath.Join(dir, "missing"), nil, nil)
+	assert.Error(t, err)
+}
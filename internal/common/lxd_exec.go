@@ -1,15 +1,21 @@
 package common
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"strings"
+	"sync"
+	"time"
 
 	lxd "github.com/canonical/lxd/client"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/gorilla/websocket"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/terraform-lxd/terraform-provider-lxd/internal/utils"
 )
 
@@ -26,21 +32,37 @@ func (t ExecTriggerType) String() string {
 	return string(t)
 }
 
+// defaultExecRetryInterval is the time to wait between retries of
+// a failed command, unless configured otherwise.
+const defaultExecRetryInterval = 5 * time.Second
+
+// execKillTimeout is the time to wait for the command to terminate
+// after it has been killed.
+const execKillTimeout = 30 * time.Second
+
+// execSignalKill is the SIGKILL signal number within the instance.
+const execSignalKill = 9
+
 // ExecModel represents exec command to be executed on LXD instance.
 type ExecModel struct {
-	Command      types.List   `tfsdk:"command"`
-	Environment  types.Map    `tfsdk:"environment"`
-	WorkingDir   types.String `tfsdk:"working_dir"`
-	Trigger      types.String `tfsdk:"trigger"`
-	Enabled      types.Bool   `tfsdk:"enabled"`
-	RecordOutput types.Bool   `tfsdk:"record_output"`
-	FailOnError  types.Bool   `tfsdk:"fail_on_error"`
-	UserID       types.Int64  `tfsdk:"uid"`
-	GroupID      types.Int64  `tfsdk:"gid"`
-	ExitCode     types.Int64  `tfsdk:"exit_code"`
-	Output       types.String `tfsdk:"stdout"`
-	Error        types.String `tfsdk:"stderr"`
-	RunCount     types.Int64  `tfsdk:"run_count"`
+	Command       types.List   `tfsdk:"command"`
+	Environment   types.Map    `tfsdk:"environment"`
+	WorkingDir    types.String `tfsdk:"working_dir"`
+	Trigger       types.String `tfsdk:"trigger"`
+	Enabled       types.Bool   `tfsdk:"enabled"`
+	RecordOutput  types.Bool   `tfsdk:"record_output"`
+	FailOnError   types.Bool   `tfsdk:"fail_on_error"`
+	UserID        types.Int64  `tfsdk:"uid"`
+	GroupID       types.Int64  `tfsdk:"gid"`
+	Stdin         types.String `tfsdk:"stdin"`
+	Timeout       types.String `tfsdk:"timeout"`
+	Retries       types.Int64  `tfsdk:"retries"`
+	RetryInterval types.String `tfsdk:"retry_interval"`
+	StreamOutput  types.Bool   `tfsdk:"stream_output"`
+	ExitCode      types.Int64  `tfsdk:"exit_code"`
+	Output        types.String `tfsdk:"stdout"`
+	Error         types.String `tfsdk:"stderr"`
+	RunCount      types.Int64  `tfsdk:"run_count"`
 }
 
 // IsTriggered determines whether the exec command needs to be executed.
@@ -66,7 +88,8 @@ func (e ExecModel) IsTriggered(isInstanceStarted bool) bool {
 }
 
 // Execute executes the exec command and populates the computed fields,
-// such as exit code, stdout, and stderr.
+// such as exit code, stdout, and stderr. If retries are configured, failed
+// command is executed again after the retry interval.
 func (e *ExecModel) Execute(ctx context.Context, server lxd.InstanceServer, instanceName string) diag.Diagnostics {
 	var diags diag.Diagnostics
 
@@ -79,6 +102,18 @@ func (e *ExecModel) Execute(ctx context.Context, server lxd.InstanceServer, inst
 		return diags
 	}
 
+	timeout, err := parseExecDuration(e.Timeout, 0)
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Invalid timeout for command %q", strings.Join(cmd, " ")), err.Error())
+		return diags
+	}
+
+	retryInterval, err := parseExecDuration(e.RetryInterval, defaultExecRetryInterval)
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Invalid retry interval for command %q", strings.Join(cmd, " ")), err.Error())
+		return diags
+	}
+
 	execReq := api.InstanceExecPost{
 		Command:      cmd,
 		Environment:  env,
@@ -86,10 +121,73 @@ func (e *ExecModel) Execute(ctx context.Context, server lxd.InstanceServer, inst
 		Interactive:  false,
 		RecordOutput: false,
 		Cwd:          e.WorkingDir.ValueString(),
-		User:         uint32(e.GroupID.ValueInt64()),
-		Group:        uint32(e.UserID.ValueInt64()),
+		User:         uint32(e.UserID.ValueInt64()),
+		Group:        uint32(e.GroupID.ValueInt64()),
 	}
 
+	var result execResult
+
+	retries := e.Retries.ValueInt64()
+	for attempt := int64(0); ; attempt++ {
+		result = e.run(ctx, server, instanceName, execReq, timeout)
+		if (result.err == nil && result.exitCode == 0) || attempt >= retries {
+			break
+		}
+
+		tflog.Debug(ctx, "Command failed, retrying", map[string]any{
+			"instance":  instanceName,
+			"command":   cmd,
+			"exit_code": result.exitCode,
+			"attempt":   attempt + 1,
+			"retries":   retries,
+		})
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(retryInterval):
+		}
+
+		if ctx.Err() != nil {
+			result.err = ctx.Err()
+			break
+		}
+	}
+
+	// Fail on error (only if user requested).
+	if e.FailOnError.ValueBool() && (result.err != nil || result.exitCode != 0) {
+		diags.AddError(
+			fmt.Sprintf("Failed to execute command on instance %q", instanceName),
+			fmt.Sprintf("Command %q failed with an error (%d): %v", strings.Join(cmd, " "), result.exitCode, result.err),
+		)
+		return diags
+	}
+
+	// Set command's computed values.
+	e.RunCount = types.Int64Value(e.RunCount.ValueInt64() + 1)
+	e.ExitCode = types.Int64Value(result.exitCode)
+	e.Output = types.StringValue(result.stdout)
+	e.Error = types.StringValue(result.stderr)
+
+	if e.RecordOutput.ValueBool() && result.err != nil {
+		// If output is recorded and error is not nil, set
+		// error as stderr, because errBuf will be empty.
+		e.Error = types.StringValue(result.err.Error())
+	}
+
+	return nil
+}
+
+// execResult represents the result of a single command execution.
+type execResult struct {
+	exitCode int64
+	stdout   string
+	stderr   string
+	err      error
+}
+
+// run executes the command once. If timeout is greater than zero, the
+// command fails if it does not complete within the given duration.
+func (e ExecModel) run(ctx context.Context, server lxd.InstanceServer, instanceName string, execReq api.InstanceExecPost, timeout time.Duration) execResult {
 	// Create buffers to capture stdout and stderr.
 	var outBuf utils.Buffer
 	var errBuf utils.Buffer
@@ -102,10 +200,46 @@ func (e *ExecModel) Execute(ctx context.Context, server lxd.InstanceServer, inst
 		errBuf = utils.NewDiscardCloser()
 	}
 
+	// The control connection is used to kill the command if it
+	// does not complete in time.
+	control := make(chan *websocket.Conn, 1)
+
 	execArgs := lxd.InstanceExecArgs{
 		Stdout:   outBuf,
 		Stderr:   errBuf,
 		DataDone: make(chan bool),
+		Control: func(conn *websocket.Conn) {
+			control <- conn
+		},
+	}
+
+	defer func() {
+		select {
+		case conn := <-control:
+			_ = conn.Close()
+		default:
+		}
+	}()
+
+	if e.StreamOutput.ValueBool() {
+		outLog := newExecLogWriter(ctx, instanceName, "stdout")
+		errLog := newExecLogWriter(ctx, instanceName, "stderr")
+		defer outLog.Flush()
+		defer errLog.Flush()
+
+		execArgs.Stdout = io.MultiWriter(outBuf, outLog)
+		execArgs.Stderr = io.MultiWriter(errBuf, errLog)
+	}
+
+	if e.Stdin.ValueString() != "" {
+		execArgs.Stdin = strings.NewReader(e.Stdin.ValueString())
+	}
+
+	runCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
 	}
 
 	// Exit code -1 indicates the command was not executed.
@@ -114,16 +248,29 @@ func (e *ExecModel) Execute(ctx context.Context, server lxd.InstanceServer, inst
 	// Run command.
 	opExec, err := server.ExecInstance(instanceName, execReq, &execArgs)
 	if err == nil {
-		err = opExec.WaitContext(ctx)
+		err = opExec.WaitContext(runCtx)
 		if err == nil {
 			// Wait for any remaining output to be flushed.
 			select {
-			case <-ctx.Done():
-				err = ctx.Err()
+			case <-runCtx.Done():
+				err = runCtx.Err()
 			case <-execArgs.DataDone:
 			}
 		}
 
+		// Ensure the command does not keep running within the instance
+		// once it is abandoned, for example, before it is retried.
+		if runCtx.Err() != nil {
+			killErr := killExec(opExec, control)
+			if killErr != nil {
+				tflog.Warn(ctx, "Failed to kill command", map[string]any{
+					"instance": instanceName,
+					"command":  execReq.Command,
+					"error":    killErr.Error(),
+				})
+			}
+		}
+
 		// Extract exit code from operation's metadata.
 		opMeta := opExec.Get().Metadata
 		if opMeta != nil {
@@ -134,30 +281,116 @@ func (e *ExecModel) Execute(ctx context.Context, server lxd.InstanceServer, inst
 		}
 	}
 
-	// Fail on error (only if user requested).
-	if e.FailOnError.ValueBool() && (err != nil || exitCode != 0) {
-		diags.AddError(
-			fmt.Sprintf("Failed to execute command on instance %q", instanceName),
-			fmt.Sprintf("Command %q failed with an error (%d): %v", strings.Join(cmd, " "), exitCode, err),
-		)
-		return diags
+	if err != nil && ctx.Err() == nil && runCtx.Err() == context.DeadlineExceeded {
+		err = fmt.Errorf("Command did not complete within %s", timeout)
 	}
 
-	// Set command's computed values.
-	e.RunCount = types.Int64Value(e.RunCount.ValueInt64() + 1)
-	e.ExitCode = types.Int64Value(exitCode)
-	e.Output = types.StringValue(outBuf.String())
-	e.Error = types.StringValue(errBuf.String())
+	return execResult{
+		exitCode: exitCode,
+		stdout:   outBuf.String(),
+		stderr:   errBuf.String(),
+		err:      err,
+	}
+}
 
-	if e.RecordOutput.ValueBool() && err != nil {
-		// If output is recorded and error is not nil, set
-		// error as stderr, because errBuf will be empty.
-		e.Error = types.StringValue(err.Error())
+// killExec kills the command of the given exec operation by sending
+// SIGKILL over the control connection, and waits for the operation
+// to complete.
+func killExec(op lxd.Operation, control <-chan *websocket.Conn) error {
+	ctx, cancel := context.WithTimeout(context.Background(), execKillTimeout)
+	defer cancel()
+
+	var conn *websocket.Conn
+
+	select {
+	case conn = <-control:
+		defer conn.Close()
+	case <-ctx.Done():
+		return fmt.Errorf("Control connection is not available")
+	}
+
+	err := conn.WriteJSON(api.InstanceExecControl{
+		Command: "signal",
+		Signal:  execSignalKill,
+	})
+	if err != nil {
+		return fmt.Errorf("Failed to send signal: %v", err)
+	}
+
+	// The operation fails once the command is killed, therefore only
+	// the timeout is reported.
+	_ = op.WaitContext(ctx)
+	if ctx.Err() != nil {
+		return fmt.Errorf("Command did not terminate within %s", execKillTimeout)
 	}
 
 	return nil
 }
 
+// execLogWriter writes the command output to the Terraform log line by line.
+type execLogWriter struct {
+	ctx      context.Context
+	instance string
+	stream   string
+	buf      []byte
+	mux      sync.Mutex
+}
+
+func newExecLogWriter(ctx context.Context, instanceName string, stream string) *execLogWriter {
+	return &execLogWriter{
+		ctx:      ctx,
+		instance: instanceName,
+		stream:   stream,
+	}
+}
+
+// Write logs each complete line and buffers the remaining output.
+func (w *execLogWriter) Write(p []byte) (int, error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+
+		w.log(string(w.buf[:i]))
+		w.buf = w.buf[i+1:]
+	}
+
+	return len(p), nil
+}
+
+// Flush logs any remaining output that is not terminated by a newline.
+func (w *execLogWriter) Flush() {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+
+	if len(w.buf) > 0 {
+		w.log(string(w.buf))
+		w.buf = nil
+	}
+}
+
+func (w *execLogWriter) log(line string) {
+	tflog.Info(w.ctx, strings.TrimSuffix(line, "\r"), map[string]any{
+		"instance": w.instance,
+		"stream":   w.stream,
+	})
+}
+
+// parseExecDuration parses the duration string, or returns the
+// default value if the duration is not set.
+func parseExecDuration(value types.String, defaultValue time.Duration) (time.Duration, error) {
+	if value.IsNull() || value.IsUnknown() || value.ValueString() == "" {
+		return defaultValue, nil
+	}
+
+	return time.ParseDuration(value.ValueString())
+}
+
 // ToExecMap converts execs schema into map of exec models.
 func ToExecMap(ctx context.Context, execMap types.Map) (map[string]*ExecModel, diag.Diagnostics) {
 	if execMap.IsNull() || execMap.IsUnknown() {
@@ -186,19 +419,24 @@ func ToExecMap(ctx context.Context, execMap types.Map) (map[string]*ExecModel, d
 // ToExecMapType converts map of exec models into schema type.
 func ToExecMapType(ctx context.Context, execs map[string]*ExecModel) (types.Map, diag.Diagnostics) {
 	execType := map[string]attr.Type{
-		"command":       types.ListType{ElemType: types.StringType},
-		"environment":   types.MapType{ElemType: types.StringType},
-		"working_dir":   types.StringType,
-		"trigger":       types.StringType,
-		"enabled":       types.BoolType,
-		"record_output": types.BoolType,
-		"fail_on_error": types.BoolType,
-		"uid":           types.Int64Type,
-		"gid":           types.Int64Type,
-		"exit_code":     types.Int64Type,
-		"stdout":        types.StringType,
-		"stderr":        types.StringType,
-		"run_count":     types.Int64Type,
+		"command":        types.ListType{ElemType: types.StringType},
+		"environment":    types.MapType{ElemType: types.StringType},
+		"working_dir":    types.StringType,
+		"trigger":        types.StringType,
+		"enabled":        types.BoolType,
+		"record_output":  types.BoolType,
+		"fail_on_error":  types.BoolType,
+		"uid":            types.Int64Type,
+		"gid":            types.Int64Type,
+		"stdin":          types.StringType,
+		"timeout":        types.StringType,
+		"retries":        types.Int64Type,
+		"retry_interval": types.StringType,
+		"stream_output":  types.BoolType,
+		"exit_code":      types.Int64Type,
+		"stdout":         types.StringType,
+		"stderr":         types.StringType,
+		"run_count":      types.Int64Type,
 	}
 
 	return types.MapValueFrom(ctx, types.ObjectType{AttrTypes: execType}, execs)
@@ -2,7 +2,10 @@ package common
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
 	"path"
 	"path/filepath"
@@ -13,19 +16,75 @@ import (
 	lxdShared "github.com/canonical/lxd/shared"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/mitchellh/go-homedir"
+	"github.com/pkg/sftp"
 	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
 )
 
+// File types supported by LXD.
+const (
+	FileTypeFile      = "file"
+	FileTypeDirectory = "directory"
+	FileTypeSymlink   = "symlink"
+)
+
 type InstanceFileModel struct {
-	Content    types.String `tfsdk:"content"`
-	SourcePath types.String `tfsdk:"source_path"`
-	TargetPath types.String `tfsdk:"target_path"`
-	UserID     types.Int64  `tfsdk:"uid"`
-	GroupID    types.Int64  `tfsdk:"gid"`
-	Mode       types.String `tfsdk:"mode"`
-	CreateDirs types.Bool   `tfsdk:"create_directories"`
-	Append     types.Bool   `tfsdk:"append"`
+	Type          types.String `tfsdk:"type"`
+	Content       types.String `tfsdk:"content"`
+	SourcePath    types.String `tfsdk:"source_path"`
+	SymlinkTarget types.String `tfsdk:"symlink_target"`
+	TargetPath    types.String `tfsdk:"target_path"`
+	UserID        types.Int64  `tfsdk:"uid"`
+	GroupID       types.Int64  `tfsdk:"gid"`
+	Mode          types.String `tfsdk:"mode"`
+	CreateDirs    types.Bool   `tfsdk:"create_directories"`
+	Append        types.Bool   `tfsdk:"append"`
+}
+
+// Validate ensures the file source matches the file type. Regular files
+// require either content or source path, symlinks require symlink target,
+// and directories require none of them. Unknown values are not validated.
+func (f InstanceFileModel) Validate() error {
+	if f.Type.IsUnknown() || f.Content.IsUnknown() || f.SourcePath.IsUnknown() || f.SymlinkTarget.IsUnknown() {
+		return nil
+	}
+
+	fileType := f.FileType()
+	hasContent := !f.Content.IsNull()
+	hasSource := !f.SourcePath.IsNull()
+	hasTarget := !f.SymlinkTarget.IsNull()
+
+	if hasContent && hasSource {
+		return fmt.Errorf("File %q and %q are mutually exclusive.", "content", "source_path")
+	}
+
+	switch fileType {
+	case FileTypeFile:
+		if !hasContent && !hasSource {
+			return fmt.Errorf("File requires either %q or %q to be set.", "content", "source_path")
+		}
+
+	case FileTypeDirectory, FileTypeSymlink:
+		if hasContent || hasSource {
+			return fmt.Errorf("File %q and %q cannot be used with file type %q.", "content", "source_path", fileType)
+		}
+	}
+
+	if hasTarget != (fileType == FileTypeSymlink) {
+		return fmt.Errorf("File %q must be set if and only if file type is %q.", "symlink_target", FileTypeSymlink)
+	}
+
+	return nil
+}
+
+// FileType returns the file type, defaulting to "file".
+func (f InstanceFileModel) FileType() string {
+	if f.Type.ValueString() == "" {
+		return FileTypeFile
+	}
+
+	return f.Type.ValueString()
 }
 
 // ToFileMap converts files from types.Set into map[string]LxdFileModel.
@@ -59,13 +118,90 @@ func ToFileSetType(ctx context.Context, fileMap map[string]InstanceFileModel) (t
 	return types.SetValueFrom(ctx, types.ObjectType{}, files)
 }
 
-// InstanceFileDelete deletes a file from an instance.
-func InstanceFileDelete(server lxd.InstanceServer, instanceName string, targetPath string) error {
+// ContentSHA256 returns SHA-256 checksum of the given content.
+func ContentSHA256(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// LocalFileSHA256 returns SHA-256 checksum of the local file contents.
+func LocalFileSHA256(filePath string) (string, error) {
+	filePath, err := homedir.Expand(filePath)
+	if err != nil {
+		return "", fmt.Errorf("Unable to determine source file path: %v", err)
+	}
+
+	f, err := os.Open(filePath)
+	if err != nil {
+		return "", fmt.Errorf("Unable to read source file: %v", err)
+	}
+	defer f.Close()
+
+	sum, err := ReaderSHA256(f)
+	if err != nil {
+		return "", fmt.Errorf("Unable to read source file: %v", err)
+	}
+
+	return sum, nil
+}
+
+// ReaderSHA256 returns SHA-256 checksum of the content read from the reader.
+func ReaderSHA256(r io.Reader) (string, error) {
+	hash := sha256.New()
+	_, err := io.Copy(hash, r)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// InstanceFileDelete deletes a file, symlink, or an empty directory from
+// an instance. Directories that are not empty are left intact, as they
+// contain files that are not managed by the caller.
+func InstanceFileDelete(ctx context.Context, server lxd.InstanceServer, instanceName string, targetPath string) error {
 	targetPath, err := toAbsFilePath(targetPath)
 	if err != nil {
 		return err
 	}
 
+	// Use SFTP to determine the file type, so the file content is not
+	// transferred.
+	sftpConn, err := server.GetInstanceFileSFTP(instanceName)
+	if err != nil {
+		return err
+	}
+
+	defer func() { _ = sftpConn.Close() }()
+
+	return deleteInstanceFile(ctx, server, sftpConn, instanceName, targetPath)
+}
+
+// deleteInstanceFile deletes a file, symlink, or an empty directory from
+// an instance using the given SFTP connection to look up the file type.
+// Directories that are not empty are skipped with a warning.
+func deleteInstanceFile(ctx context.Context, server lxd.InstanceServer, sftpConn *sftp.Client, instanceName string, targetPath string) error {
+	info, err := sftpConn.Lstat(targetPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return err
+	}
+
+	if info.IsDir() {
+		entries, err := sftpConn.ReadDir(targetPath)
+		if err != nil {
+			return err
+		}
+
+		if len(entries) > 0 {
+			tflog.Warn(ctx, "Skipped deletion of non-empty directory", map[string]any{"instance": instanceName, "path": targetPath})
+			return nil
+		}
+	}
+
 	err = server.DeleteInstanceFile(instanceName, targetPath)
 	if err != nil && !errors.IsNotFoundError(err) {
 		return err
@@ -74,14 +210,18 @@ func InstanceFileDelete(server lxd.InstanceServer, instanceName string, targetPa
 	return nil
 }
 
-// InstanceFileUpload uploads a file to an instance.
+// InstanceFileUpload uploads a file to an instance, or creates a directory
+// or a symlink, depending on the file type.
 func InstanceFileUpload(server lxd.InstanceServer, instanceName string, file InstanceFileModel) error {
+	err := file.Validate()
+	if err != nil {
+		return err
+	}
+
+	fileType := file.FileType()
 	content := file.Content.ValueString()
 	sourcePath := file.SourcePath.ValueString()
-
-	if content != "" && sourcePath != "" {
-		return fmt.Errorf("File %q and %q are mutually exclusive.", "content", "source_path")
-	}
+	symlinkTarget := file.SymlinkTarget.ValueString()
 
 	targetPath, err := toAbsFilePath(file.TargetPath.ValueString())
 	if err != nil {
@@ -100,12 +240,17 @@ func InstanceFileUpload(server lxd.InstanceServer, instanceName string, file Ins
 
 	// Build the file creation request, without the content.
 	args := &lxd.InstanceFileArgs{
-		Type: "file",
+		Type: fileType,
 		Mode: int(mode),
 		UID:  file.UserID.ValueInt64(),
 		GID:  file.GroupID.ValueInt64(),
 	}
 
+	// Symlink target is sent as the content.
+	if fileType == FileTypeSymlink {
+		args.Content = strings.NewReader(symlinkTarget)
+	}
+
 	if file.Append.ValueBool() {
 		args.WriteMode = "append"
 	} else {
@@ -200,17 +345,16 @@ func recursiveMkdir(server lxd.InstanceServer, instanceName string, p string, ar
 	return nil
 }
 
-// toAbsFilePath returns absolute path of the given path and ensures that
-// the path is not a directory.
+// toAbsFilePath returns absolute path of the given path. Trailing slashes
+// are removed, so the path may point to a file, a directory, or a symlink.
 func toAbsFilePath(path string) (string, error) {
 	targetPath, err := filepath.Abs(path)
 	if err != nil {
 		return "", fmt.Errorf("Failed to determine absoulute target file path: %v", err)
 	}
 
-	isDir := strings.HasSuffix(targetPath, "/")
-	if isDir {
-		return "", fmt.Errorf("Target file %q cannot be a directory: %v", targetPath, err)
+	if targetPath == "/" {
+		return "", fmt.Errorf("Target path cannot be the root directory")
 	}
 
 	return targetPath, nil
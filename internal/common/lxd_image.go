@@ -0,0 +1,90 @@
+package common
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	lxd "github.com/canonical/lxd/client"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/utils"
+)
+
+// ResolveImageArchitecture returns the fingerprint of the image with the
+// given name and architecture. The name can be either an image alias or
+// a fingerprint. In the latter case, the architecture of the image is
+// verified.
+func ResolveImageArchitecture(server lxd.ImageServer, imageType string, name string, architecture string) (string, error) {
+	entries, err := server.GetImageAliasArchitectures(imageType, name)
+	if err != nil {
+		// Name may refer to an image fingerprint.
+		image, _, errImage := server.GetImage(name)
+		if errImage != nil {
+			return "", fmt.Errorf("Failed to resolve image %q: %v", name, err)
+		}
+
+		if image.Architecture != architecture {
+			return "", fmt.Errorf("Image %q has architecture %q, but %q was requested", name, image.Architecture, architecture)
+		}
+
+		return image.Fingerprint, nil
+	}
+
+	entry, ok := entries[architecture]
+	if !ok {
+		available := strings.Join(utils.SortMapKeys(entries), ", ")
+		return "", fmt.Errorf("No image alias %q found for architecture %q. Available architectures: %s", name, architecture, available)
+	}
+
+	return entry.Target, nil
+}
+
+// ServerArchitectures returns architectures supported by the server. On
+// a cluster, the architectures of the target member are returned. If no
+// member or a cluster group is targeted, the architectures of all
+// matching cluster members are returned.
+func ServerArchitectures(server lxd.InstanceServer, target string) ([]string, error) {
+	if server.IsClustered() && (target == "" || strings.HasPrefix(target, "@")) {
+		members, err := server.GetClusterMembers()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to retrieve cluster members: %v", err)
+		}
+
+		group := strings.TrimPrefix(target, "@")
+
+		var architectures []string
+		for _, member := range members {
+			if group != "" && !slices.Contains(member.Groups, group) {
+				continue
+			}
+
+			if !slices.Contains(architectures, member.Architecture) {
+				architectures = append(architectures, member.Architecture)
+			}
+		}
+
+		return architectures, nil
+	}
+
+	info, _, err := server.UseTarget(target).GetServer()
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve server info: %v", err)
+	}
+
+	return info.Environment.Architectures, nil
+}
+
+// ValidateServerArchitecture checks whether the server supports the given
+// architecture. See ServerArchitectures for details on how the target is
+// evaluated.
+func ValidateServerArchitecture(server lxd.InstanceServer, target string, architecture string) error {
+	architectures, err := ServerArchitectures(server, target)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(architectures, architecture) {
+		return fmt.Errorf("Architecture %q is not supported by the server. Supported architectures: %s", architecture, strings.Join(architectures, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,149 @@
+package common
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/canonical/lxd/shared/api"
+
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/utils"
+)
+
+// RestartRequiredChanges compares the old and new instance config and devices,
+// and returns config keys and device properties (formatted as "device.<name>.<key>")
+// whose changes cannot be applied to a running instance of the given type.
+//
+// Live update support is determined from the LXD configuration metadata. Keys that
+// are not found in the metadata are considered live updatable. Added and removed
+// devices are not reported, as LXD either hotplugs them or rejects the update.
+func RestartRequiredChanges(meta *api.MetadataConfiguration, instanceType string, oldConfig map[string]string, newConfig map[string]string, oldDevices map[string]map[string]string, newDevices map[string]map[string]string) []string {
+	if meta == nil {
+		return nil
+	}
+
+	var keys []string
+
+	instanceKeys := metadataEntityKeys(meta, "instance")
+	for _, k := range changedKeys(oldConfig, newConfig) {
+		if !isLiveUpdatable(instanceKeys, k, instanceType) {
+			keys = append(keys, k)
+		}
+	}
+
+	for _, name := range utils.SortMapKeys(newDevices) {
+		newDevice := newDevices[name]
+		oldDevice, ok := oldDevices[name]
+		if !ok || oldDevice["type"] != newDevice["type"] {
+			continue
+		}
+
+		deviceKeys := metadataDeviceKeys(meta, newDevice)
+		for _, k := range changedKeys(oldDevice, newDevice) {
+			if !isLiveUpdatable(deviceKeys, k, instanceType) {
+				keys = append(keys, fmt.Sprintf("device.%s.%s", name, k))
+			}
+		}
+	}
+
+	return keys
+}
+
+// changedKeys returns sorted keys that were added, removed, or modified.
+func changedKeys(oldMap map[string]string, newMap map[string]string) []string {
+	var keys []string
+
+	for _, k := range utils.SortMapKeys(oldMap) {
+		newValue, ok := newMap[k]
+		if !ok || newValue != oldMap[k] {
+			keys = append(keys, k)
+		}
+	}
+
+	for _, k := range utils.SortMapKeys(newMap) {
+		_, ok := oldMap[k]
+		if !ok {
+			keys = append(keys, k)
+		}
+	}
+
+	return keys
+}
+
+// metadataEntityKeys returns config keys of all groups of the given entity.
+func metadataEntityKeys(meta *api.MetadataConfiguration, entity string) map[string]api.MetadataConfigKey {
+	keys := make(map[string]api.MetadataConfigKey)
+
+	for _, group := range meta.Configs[entity] {
+		for _, entry := range group.Keys {
+			for k, v := range entry {
+				keys[k] = v
+			}
+		}
+	}
+
+	return keys
+}
+
+// metadataDeviceKeys returns config keys of the given device. Keys of the
+// device subtype entity (e.g. "device-nic-bridged") take precedence over
+// keys of the generic device entity (e.g. "device-nic").
+func metadataDeviceKeys(meta *api.MetadataConfiguration, device map[string]string) map[string]api.MetadataConfigKey {
+	entity := "device-" + device["type"]
+	keys := metadataEntityKeys(meta, entity)
+
+	for _, subtypeKey := range []string{"nictype", "gputype"} {
+		subtype := device[subtypeKey]
+		if subtype == "" {
+			continue
+		}
+
+		for k, v := range metadataEntityKeys(meta, entity+"-"+subtype) {
+			keys[k] = v
+		}
+	}
+
+	return keys
+}
+
+// isLiveUpdatable determines whether the given key can be updated on
+// a running instance of the given type.
+func isLiveUpdatable(keys map[string]api.MetadataConfigKey, key string, instanceType string) bool {
+	meta, ok := keys[key]
+	if !ok {
+		// Match wildcard keys, such as "user.*".
+		for _, k := range utils.SortMapKeys(keys) {
+			prefix, _, found := strings.Cut(k, "*")
+			if found && strings.HasPrefix(key, prefix) {
+				meta = keys[k]
+				ok = true
+				break
+			}
+		}
+	}
+
+	if !ok {
+		return true
+	}
+
+	typeName := "container"
+	if instanceType == string(api.InstanceTypeVM) {
+		typeName = "virtual machine"
+	}
+
+	// Key is not applicable to the instance type.
+	condition := strings.ToLower(meta.Condition)
+	if (condition == "container" || condition == "virtual machine") && condition != typeName {
+		return true
+	}
+
+	liveUpdate := strings.ToLower(meta.LiveUpdate)
+	switch liveUpdate {
+	case "", "yes":
+		return true
+	case "no":
+		return false
+	}
+
+	// For example "Only for containers".
+	return strings.Contains(liveUpdate, typeName)
+}
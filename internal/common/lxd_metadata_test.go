@@ -0,0 +1,133 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/stretchr/testify/assert"
+)
+
+func testMetadataConfiguration() *api.MetadataConfiguration {
+	return &api.MetadataConfiguration{
+		Configs: map[string]map[string]api.MetadataConfigGroup{
+			"instance": {
+				"resource-limits": {
+					Keys: []map[string]api.MetadataConfigKey{
+						{"limits.cpu": {LiveUpdate: "yes"}},
+						{"limits.memory": {LiveUpdate: "yes"}},
+						{"limits.cpu.nodes": {LiveUpdate: "no"}},
+					},
+				},
+				"security": {
+					Keys: []map[string]api.MetadataConfigKey{
+						{"security.privileged": {LiveUpdate: "no", Condition: "container"}},
+						{"security.secureboot": {LiveUpdate: "no", Condition: "virtual machine"}},
+						{"security.nesting": {LiveUpdate: "Only for containers"}},
+					},
+				},
+				"miscellaneous": {
+					Keys: []map[string]api.MetadataConfigKey{
+						{"user.*": {LiveUpdate: "yes"}},
+						{"environment.*": {LiveUpdate: "no"}},
+					},
+				},
+			},
+			"device-nic": {
+				"device-conf": {
+					Keys: []map[string]api.MetadataConfigKey{
+						{"hwaddr": {LiveUpdate: "no"}},
+						{"limits.ingress": {LiveUpdate: "yes"}},
+					},
+				},
+			},
+			"device-nic-bridged": {
+				"device-conf": {
+					Keys: []map[string]api.MetadataConfigKey{
+						{"limits.ingress": {LiveUpdate: "no"}},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestRestartRequiredChanges_config(t *testing.T) {
+	meta := testMetadataConfiguration()
+
+	tests := []struct {
+		Name         string
+		InstanceType string
+		OldConfig    map[string]string
+		NewConfig    map[string]string
+		Result       []string
+	}{
+		{
+			Name:      "Live keys",
+			OldConfig: map[string]string{"limits.cpu": "1"},
+			NewConfig: map[string]string{"limits.cpu": "2", "limits.memory": "1GiB", "user.foo": "bar"},
+		},
+		{
+			Name:      "Unknown keys",
+			NewConfig: map[string]string{"unknown.key": "true"},
+		},
+		{
+			Name:      "Modified restart key",
+			OldConfig: map[string]string{"limits.cpu.nodes": "0"},
+			NewConfig: map[string]string{"limits.cpu.nodes": "1"},
+			Result:    []string{"limits.cpu.nodes"},
+		},
+		{
+			Name:      "Removed restart key",
+			OldConfig: map[string]string{"limits.cpu.nodes": "0", "environment.FOO": "bar"},
+			Result:    []string{"environment.FOO", "limits.cpu.nodes"},
+		},
+		{
+			Name:         "Condition container",
+			InstanceType: "container",
+			NewConfig:    map[string]string{"security.privileged": "true", "security.secureboot": "false", "security.nesting": "true"},
+			Result:       []string{"security.privileged"},
+		},
+		{
+			Name:         "Condition virtual machine",
+			InstanceType: "virtual-machine",
+			NewConfig:    map[string]string{"security.privileged": "true", "security.secureboot": "false", "security.nesting": "true"},
+			Result:       []string{"security.nesting", "security.secureboot"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			instanceType := test.InstanceType
+			if instanceType == "" {
+				instanceType = "container"
+			}
+
+			result := RestartRequiredChanges(meta, instanceType, test.OldConfig, test.NewConfig, nil, nil)
+			assert.Equal(t, test.Result, result)
+		})
+	}
+}
+
+func TestRestartRequiredChanges_devices(t *testing.T) {
+	meta := testMetadataConfiguration()
+
+	oldDevices := map[string]map[string]string{
+		"eth0": {"type": "nic", "network": "lxdbr0"},
+		"eth1": {"type": "nic", "nictype": "bridged", "parent": "br0"},
+		"root": {"type": "disk", "path": "/", "pool": "default"},
+	}
+
+	newDevices := map[string]map[string]string{
+		"eth0": {"type": "nic", "network": "lxdbr0", "hwaddr": "00:16:3e:00:00:01", "limits.ingress": "10Mbit"},
+		"eth1": {"type": "nic", "nictype": "bridged", "parent": "br0", "limits.ingress": "10Mbit"},
+		"eth2": {"type": "nic", "network": "lxdbr0", "hwaddr": "00:16:3e:00:00:02"},
+		"root": {"type": "disk", "path": "/", "pool": "default", "size": "10GiB"},
+	}
+
+	result := RestartRequiredChanges(meta, "container", nil, nil, oldDevices, newDevices)
+	assert.Equal(t, []string{"device.eth0.hwaddr", "device.eth1.limits.ingress"}, result)
+
+	// Nil metadata.
+	result = RestartRequiredChanges(nil, "container", nil, nil, oldDevices, newDevices)
+	assert.Nil(t, result)
+}
@@ -0,0 +1,67 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/canonical/lxd/shared/osarch"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+)
+
+// ArchitectureValidator ensures value is an architecture supported by LXD.
+// Empty value is ignored.
+type ArchitectureValidator struct{}
+
+func (v ArchitectureValidator) Description(ctx context.Context) string {
+	supportedArchitecturesList := strings.Join(osarch.SupportedArchitectures(), ", ")
+	return fmt.Sprintf("Attribute architecture value must be one of: %s.", supportedArchitecturesList)
+}
+
+func (v ArchitectureValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v ArchitectureValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	value := req.ConfigValue.ValueString()
+	if value == "" {
+		return
+	}
+
+	for _, supportedArchitecture := range osarch.SupportedArchitectures() {
+		if value == supportedArchitecture {
+			return
+		}
+	}
+
+	resp.Diagnostics.AddAttributeError(req.Path, "Invalid architecture",
+		v.Description(ctx),
+	)
+}
+
+// TimestampValidator ensures value is a valid RFC 3339 timestamp.
+type TimestampValidator struct{}
+
+func (v TimestampValidator) Description(ctx context.Context) string {
+	return "value must be a valid RFC 3339 timestamp"
+}
+
+func (v TimestampValidator) MarkdownDescription(ctx context.Context) string {
+	return "value must be a valid RFC 3339 timestamp"
+}
+
+func (v TimestampValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+
+	_, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid timestamp",
+			fmt.Sprintf("Value must be a valid RFC 3339 timestamp, e.g. %q. Got: %q.", "2030-01-02T15:04:05Z", value),
+		)
+	}
+}
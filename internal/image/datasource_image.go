@@ -3,7 +3,6 @@ package image
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	lxd "github.com/canonical/lxd/client"
 	"github.com/canonical/lxd/shared/api"
@@ -12,6 +11,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/common"
 	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
 	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
 )
@@ -63,7 +63,7 @@ func (d *ImageDataSource) Schema(_ context.Context, req datasource.SchemaRequest
 				Optional: true,
 				Computed: true,
 				Validators: []validator.String{
-					architectureValidator{},
+					common.ArchitectureValidator{},
 				},
 			},
 
@@ -155,29 +155,9 @@ func (d *ImageDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		architecture := state.Architecture.ValueString()
 
 		if architecture != "" {
-			imageType := state.Type.ValueString()
-			availableArchitectures, err := server.GetImageAliasArchitectures(imageType, imageName)
+			fingerprint, err = common.ResolveImageArchitecture(server, state.Type.ValueString(), imageName, architecture)
 			if err != nil {
-				resp.Diagnostics.AddError("Failed to get image alias architectures", err.Error())
-				return
-			}
-
-			found := false
-			for imageArchitecture, imageAlias := range availableArchitectures {
-				if imageArchitecture == architecture {
-					fingerprint = imageAlias.Target
-					found = true
-				}
-			}
-
-			if !found {
-				keys := make([]string, 0, len(availableArchitectures))
-				for key := range availableArchitectures {
-					keys = append(keys, key)
-				}
-				keyList := strings.Join(keys, ", ")
-
-				resp.Diagnostics.AddError(fmt.Sprintf("No image alias found for architecture: %s. Available architectures: %s ", architecture, keyList), "")
+				resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve image %q for architecture %q", imageName, architecture), err.Error())
 				return
 			}
 		} else {
@@ -209,6 +189,15 @@ func (d *ImageDataSource) Read(ctx context.Context, req datasource.ReadRequest,
 		return
 	}
 
+	architecture := state.Architecture.ValueString()
+	if architecture != "" && image.Architecture != architecture {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Image %q does not match the requested architecture", fingerprint),
+			fmt.Sprintf("Image has architecture %q, but %q was requested.", image.Architecture, architecture),
+		)
+		return
+	}
+
 	var aliases []string
 	for _, a := range image.Aliases {
 		aliases = append(aliases, a.Name)
@@ -47,6 +47,23 @@ func TestAccImage_DS_basicVM(t *testing.T) {
 	})
 }
 
+func TestAccImage_DS_architecture(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccImage_DS_architecture(testImageArchitecture()),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.lxd_image.img", "name", acctest.TestCachedImageSourceImage),
+					resource.TestCheckResourceAttr("data.lxd_image.img", "architecture", testImageArchitecture()),
+					resource.TestCheckResourceAttrSet("data.lxd_image.img", "fingerprint"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccImage_DS_cached(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck:                 func() { acctest.PreCheck(t) },
@@ -107,6 +124,16 @@ data "lxd_image" "img" {
 	`, acctest.TestCachedImageSourceImage, acctest.TestCachedImageSourceRemote)
 }
 
+func testAccImage_DS_architecture(architecture string) string {
+	return fmt.Sprintf(`
+data "lxd_image" "img" {
+  name         = %q
+  architecture = %q
+  remote       = %q
+}
+	`, acctest.TestCachedImageSourceImage, architecture, acctest.TestCachedImageSourceRemote)
+}
+
 func testAccImage_DS_cached(aliases ...string) string {
 	return fmt.Sprintf(`
 resource "lxd_cached_image" "img" {
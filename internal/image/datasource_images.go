@@ -0,0 +1,304 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	lxd "github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/common"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
+	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
+)
+
+type ImagesDataSourceModel struct {
+	Remote       types.String `tfsdk:"remote"`
+	Project      types.String `tfsdk:"project"`
+	Type         types.String `tfsdk:"type"`
+	Architecture types.String `tfsdk:"architecture"`
+	AliasPrefix  types.String `tfsdk:"alias_prefix"`
+	Properties   types.Map    `tfsdk:"properties"`
+	Limit        types.Int64  `tfsdk:"limit"`
+
+	// Computed
+	Images types.List `tfsdk:"images"`
+}
+
+// ImagesItemModel represents a single image returned by the lxd_images
+// data source.
+type ImagesItemModel struct {
+	Fingerprint  types.String `tfsdk:"fingerprint"`
+	Aliases      types.List   `tfsdk:"aliases"`
+	Architecture types.String `tfsdk:"architecture"`
+	Type         types.String `tfsdk:"type"`
+	CreatedAt    types.Int64  `tfsdk:"created_at"`
+	Properties   types.Map    `tfsdk:"properties"`
+}
+
+var imagesItemType = map[string]attr.Type{
+	"fingerprint":  types.StringType,
+	"aliases":      types.ListType{ElemType: types.StringType},
+	"architecture": types.StringType,
+	"type":         types.StringType,
+	"created_at":   types.Int64Type,
+	"properties":   types.MapType{ElemType: types.StringType},
+}
+
+type ImagesDataSource struct {
+	provider *provider_config.LxdProviderConfig
+}
+
+func NewImagesDataSource() datasource.DataSource {
+	return &ImagesDataSource{}
+}
+
+func (d *ImagesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = fmt.Sprintf("%s_images", req.ProviderTypeName)
+}
+
+func (d *ImagesDataSource) Schema(_ context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"remote": schema.StringAttribute{
+				Optional: true,
+			},
+
+			"project": schema.StringAttribute{
+				Optional: true,
+			},
+
+			"type": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("container", "virtual-machine"),
+				},
+			},
+
+			"architecture": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					common.ArchitectureValidator{},
+				},
+			},
+
+			"alias_prefix": schema.StringAttribute{
+				Optional:    true,
+				Description: "List only images with an alias starting with the given prefix",
+			},
+
+			"properties": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "List only images with matching properties",
+			},
+
+			"limit": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Maximum number of images to return",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+
+			// Computed.
+
+			"images": schema.ListNestedAttribute{
+				Computed:    true,
+				Description: "Matching images, sorted from newest to oldest",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"fingerprint": schema.StringAttribute{
+							Computed: true,
+						},
+
+						"aliases": schema.ListAttribute{
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+
+						"architecture": schema.StringAttribute{
+							Computed: true,
+						},
+
+						"type": schema.StringAttribute{
+							Computed: true,
+						},
+
+						"created_at": schema.Int64Attribute{
+							Computed: true,
+						},
+
+						"properties": schema.MapAttribute{
+							Computed:    true,
+							ElementType: types.StringType,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *ImagesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	data := req.ProviderData
+	if data == nil {
+		return
+	}
+
+	provider, ok := data.(*provider_config.LxdProviderConfig)
+	if !ok {
+		resp.Diagnostics.Append(errors.NewProviderDataTypeError(req.ProviderData))
+		return
+	}
+
+	d.provider = provider
+}
+
+func (d *ImagesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state ImagesDataSourceModel
+
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := state.Remote.ValueString()
+	server, err := d.provider.ImageServer(remote)
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewImageServerError(err))
+		return
+	}
+
+	// Set project if we are dealing with instance server.
+	instServer, ok := server.(lxd.InstanceServer)
+	if ok {
+		server = instServer.UseProject(state.Project.ValueString())
+	}
+
+	properties, diags := common.ToConfigMap(ctx, state.Properties)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	images, err := server.GetImages()
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to retrieve images", err.Error())
+		return
+	}
+
+	filter := imagesFilter{
+		imageType:    state.Type.ValueString(),
+		architecture: state.Architecture.ValueString(),
+		aliasPrefix:  state.AliasPrefix.ValueString(),
+		properties:   properties,
+	}
+
+	images = filter.apply(images)
+
+	limit := int(state.Limit.ValueInt64())
+	if limit > 0 && len(images) > limit {
+		images = images[:limit]
+	}
+
+	items := make([]ImagesItemModel, 0, len(images))
+	for _, image := range images {
+		aliases := make([]string, 0, len(image.Aliases))
+		for _, a := range image.Aliases {
+			aliases = append(aliases, a.Name)
+		}
+
+		slices.Sort(aliases)
+
+		aliasList, diags := types.ListValueFrom(ctx, types.StringType, aliases)
+		resp.Diagnostics.Append(diags...)
+
+		props, diags := types.MapValueFrom(ctx, types.StringType, image.Properties)
+		resp.Diagnostics.Append(diags...)
+
+		items = append(items, ImagesItemModel{
+			Fingerprint:  types.StringValue(image.Fingerprint),
+			Aliases:      aliasList,
+			Architecture: types.StringValue(image.Architecture),
+			Type:         types.StringValue(image.Type),
+			CreatedAt:    types.Int64Value(image.CreatedAt.Unix()),
+			Properties:   props,
+		})
+	}
+
+	state.Images, diags = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: imagesItemType}, items)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// imagesFilter filters images by their type, architecture, aliases, and
+// properties. Empty filter values match any image.
+type imagesFilter struct {
+	imageType    string
+	architecture string
+	aliasPrefix  string
+	properties   map[string]string
+}
+
+// match returns true if the image matches all filter values.
+func (f imagesFilter) match(image api.Image) bool {
+	if f.imageType != "" && image.Type != f.imageType {
+		return false
+	}
+
+	if f.architecture != "" && image.Architecture != f.architecture {
+		return false
+	}
+
+	for k, v := range f.properties {
+		if image.Properties[k] != v {
+			return false
+		}
+	}
+
+	if f.aliasPrefix != "" {
+		return slices.ContainsFunc(image.Aliases, func(a api.ImageAlias) bool {
+			return strings.HasPrefix(a.Name, f.aliasPrefix)
+		})
+	}
+
+	return true
+}
+
+// apply returns images that match the filter, sorted by creation date
+// from newest to oldest. Images created at the same time are sorted by
+// fingerprint to ensure consistent ordering.
+func (f imagesFilter) apply(images []api.Image) []api.Image {
+	result := make([]api.Image, 0, len(images))
+	for _, image := range images {
+		if f.match(image) {
+			result = append(result, image)
+		}
+	}
+
+	slices.SortFunc(result, func(a api.Image, b api.Image) int {
+		c := b.CreatedAt.Compare(a.CreatedAt)
+		if c != 0 {
+			return c
+		}
+
+		return strings.Compare(a.Fingerprint, b.Fingerprint)
+	})
+
+	return result
+}
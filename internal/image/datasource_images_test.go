@@ -0,0 +1,136 @@
+package image_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/acctest"
+)
+
+func TestAccImages_DS_simplestreams(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccImages_DS_simplestreams(testImageArchitecture()),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.lxd_images.imgs", "images.#", "1"),
+					resource.TestCheckResourceAttr("data.lxd_images.imgs", "images.0.type", "virtual-machine"),
+					resource.TestCheckResourceAttr("data.lxd_images.imgs", "images.0.architecture", testImageArchitecture()),
+					resource.TestCheckResourceAttrSet("data.lxd_images.imgs", "images.0.fingerprint"),
+					resource.TestCheckResourceAttrSet("data.lxd_images.imgs", "images.0.created_at"),
+					resource.TestCheckTypeSetElemAttr("data.lxd_images.imgs", "images.0.aliases.*", acctest.TestCachedImageSourceImage),
+				),
+			},
+		},
+	})
+}
+
+func TestAccImages_DS_properties(t *testing.T) {
+	alias := acctest.GenerateName(2, "-")
+	variant := acctest.GenerateName(2, "-")
+	dir := t.TempDir()
+	imagePath1 := filepath.Join(dir, "image1.tar.gz")
+	imagePath2 := filepath.Join(dir, "image2.tar.gz")
+	writeTestImageTarball(t, imagePath1, true, "v1")
+	writeTestImageTarball(t, imagePath2, true, "v2")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccImages_DS_properties(imagePath1, imagePath2, alias, variant),
+				Check: resource.ComposeTestCheckFunc(
+					// Match by properties.
+					resource.TestCheckResourceAttr("data.lxd_images.variant", "images.#", "2"),
+					resource.TestCheckResourceAttr("data.lxd_images.variant", "images.0.properties.variant", variant),
+					resource.TestCheckResourceAttr("data.lxd_images.variant", "images.1.properties.variant", variant),
+
+					// Match by properties and alias prefix.
+					resource.TestCheckResourceAttr("data.lxd_images.alias", "images.#", "1"),
+					resource.TestCheckResourceAttrPair("data.lxd_images.alias", "images.0.fingerprint", "lxd_image.img2", "fingerprint"),
+					resource.TestCheckResourceAttr("data.lxd_images.alias", "images.0.aliases.#", "1"),
+					resource.TestCheckResourceAttr("data.lxd_images.alias", "images.0.aliases.0", alias+"-v2"),
+
+					// No match.
+					resource.TestCheckResourceAttr("data.lxd_images.none", "images.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccImages_DS_simplestreams(architecture string) string {
+	return fmt.Sprintf(`
+data "lxd_images" "imgs" {
+  remote       = %q
+  type         = "virtual-machine"
+  architecture = %q
+  alias_prefix = %q
+  limit        = 1
+}
+	`, acctest.TestCachedImageSourceRemote, architecture, acctest.TestCachedImageSourceImage)
+}
+
+func testAccImages_DS_properties(imagePath1 string, imagePath2 string, alias string, variant string) string {
+	return fmt.Sprintf(`
+resource "lxd_image" "img1" {
+  metadata_path = %[1]q
+  aliases       = ["%[3]s-v1"]
+
+  properties = {
+    variant = %[4]q
+  }
+}
+
+resource "lxd_image" "img2" {
+  metadata_path = %[2]q
+  aliases       = ["%[3]s-v2"]
+
+  properties = {
+    variant = %[4]q
+  }
+}
+
+data "lxd_images" "variant" {
+  properties = {
+    variant = %[4]q
+  }
+
+  depends_on = [
+    lxd_image.img1,
+    lxd_image.img2,
+  ]
+}
+
+data "lxd_images" "alias" {
+  alias_prefix = "%[3]s-v2"
+
+  properties = {
+    variant = %[4]q
+  }
+
+  depends_on = [
+    lxd_image.img1,
+    lxd_image.img2,
+  ]
+}
+
+data "lxd_images" "none" {
+  type = "virtual-machine"
+
+  properties = {
+    variant = %[4]q
+  }
+
+  depends_on = [
+    lxd_image.img1,
+    lxd_image.img2,
+  ]
+}
+	`, imagePath1, imagePath2, alias, variant)
+}
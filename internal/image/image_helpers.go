@@ -0,0 +1,314 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	lxd "github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/common"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/utils"
+)
+
+// ToImagePropertiesMapType converts image properties into types.Map. Only
+// properties present in the model are retained, as images usually contain
+// additional properties that originate from the image metadata.
+func ToImagePropertiesMapType(ctx context.Context, imageProps map[string]string, modelProps types.Map) (types.Map, diag.Diagnostics) {
+	if modelProps.IsNull() || modelProps.IsUnknown() {
+		return types.MapNull(types.StringType), nil
+	}
+
+	configProps, diags := common.ToConfigMap(ctx, modelProps)
+	if diags.HasError() {
+		return types.MapNull(types.StringType), diags
+	}
+
+	props := make(map[string]string, len(configProps))
+	for k := range configProps {
+		v, ok := imageProps[k]
+		if ok {
+			props[k] = v
+		}
+	}
+
+	return types.MapValueFrom(ctx, types.StringType, props)
+}
+
+// mergeImageProperties returns image properties with properties removed
+// from the configuration deleted and the configured properties applied.
+// Properties that were never managed by the configuration are retained.
+func mergeImageProperties(imageProps map[string]string, oldProps map[string]string, newProps map[string]string) map[string]string {
+	props := make(map[string]string, len(imageProps)+len(newProps))
+	for k, v := range imageProps {
+		_, managed := oldProps[k]
+		if !managed {
+			props[k] = v
+		}
+	}
+
+	for k, v := range newProps {
+		props[k] = v
+	}
+
+	return props
+}
+
+// imageSettings holds image settings that can be updated in place. Nil
+// profiles and expiry are not managed and are left unchanged.
+type imageSettings struct {
+	public     bool
+	autoUpdate bool
+	oldProps   map[string]string
+	newProps   map[string]string
+	profiles   []string
+	expiresAt  *time.Time
+}
+
+// toImageSettings converts the image settings from their Terraform types.
+// Old properties are the properties managed by the previous configuration.
+func toImageSettings(ctx context.Context, public types.Bool, autoUpdate types.Bool, oldProps types.Map, newProps types.Map, profiles types.List, expiresAt types.String) (imageSettings, diag.Diagnostics) {
+	var respDiags diag.Diagnostics
+
+	s := imageSettings{
+		public:     public.ValueBool(),
+		autoUpdate: autoUpdate.ValueBool(),
+	}
+
+	var diags diag.Diagnostics
+
+	s.oldProps, diags = common.ToConfigMap(ctx, oldProps)
+	respDiags.Append(diags...)
+
+	s.newProps, diags = common.ToConfigMap(ctx, newProps)
+	respDiags.Append(diags...)
+
+	if !profiles.IsNull() && !profiles.IsUnknown() {
+		s.profiles = make([]string, 0, len(profiles.Elements()))
+		respDiags.Append(profiles.ElementsAs(ctx, &s.profiles, false)...)
+	}
+
+	if !expiresAt.IsNull() && !expiresAt.IsUnknown() {
+		t, err := time.Parse(time.RFC3339, expiresAt.ValueString())
+		if err != nil {
+			respDiags.AddAttributeError(path.Root("expires_at"), "Invalid image expiry", err.Error())
+		}
+
+		s.expiresAt = &t
+	}
+
+	return s, respDiags
+}
+
+// updateImageSettings applies the settings to the image with the given
+// fingerprint.
+func updateImageSettings(server lxd.InstanceServer, fingerprint string, s imageSettings) error {
+	image, etag, err := server.GetImage(fingerprint)
+	if err != nil {
+		return err
+	}
+
+	imageReq := image.Writable()
+	imageReq.Public = s.public
+	imageReq.AutoUpdate = s.autoUpdate
+	imageReq.Properties = mergeImageProperties(image.Properties, s.oldProps, s.newProps)
+
+	if s.profiles != nil {
+		imageReq.Profiles = s.profiles
+	}
+
+	if s.expiresAt != nil {
+		imageReq.ExpiresAt = *s.expiresAt
+	}
+
+	return server.UpdateImage(fingerprint, imageReq, etag)
+}
+
+// importImage returns the image referenced by the import ID, which is
+// either an image alias or a fingerprint.
+func importImage(server lxd.InstanceServer, name string) (*api.Image, error) {
+	alias, _, err := server.GetImageAlias(name)
+	if err == nil {
+		name = alias.Target
+	} else if !errors.IsNotFoundError(err) {
+		return nil, fmt.Errorf("Failed to retrieve image alias %q: %v", name, err)
+	}
+
+	image, _, err := server.GetImage(name)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve image %q: %v", name, err)
+	}
+
+	return image, nil
+}
+
+// importImageSettings sets the image settings that are tracked only when
+// configured from the "properties", "profiles", and "expires_at" import
+// options, and removes these options from the fields. Properties and
+// profiles are lists separated by slash. Property values are taken from
+// the image, as only the keys of the managed properties are provided.
+func importImageSettings(ctx context.Context, state *tfsdk.State, image *api.Image, fields map[string]string) diag.Diagnostics {
+	var respDiags diag.Diagnostics
+
+	if fields["properties"] != "" {
+		props := make(map[string]string)
+		for _, k := range strings.Split(fields["properties"], "/") {
+			v, ok := image.Properties[k]
+			if ok {
+				props[k] = v
+			}
+		}
+
+		respDiags.Append(state.SetAttribute(ctx, path.Root("properties"), props)...)
+	}
+
+	if fields["profiles"] != "" {
+		profiles := strings.Split(fields["profiles"], "/")
+		respDiags.Append(state.SetAttribute(ctx, path.Root("profiles"), profiles)...)
+	}
+
+	if fields["expires_at"] != "" {
+		_, err := time.Parse(time.RFC3339, fields["expires_at"])
+		if err != nil {
+			respDiags.AddError(
+				fmt.Sprintf("Failed to import image %q", image.Fingerprint),
+				fmt.Sprintf("Invalid value %q for option \"expires_at\": %v", fields["expires_at"], err),
+			)
+		} else {
+			respDiags.Append(state.SetAttribute(ctx, path.Root("expires_at"), fields["expires_at"])...)
+		}
+	}
+
+	delete(fields, "properties")
+	delete(fields, "profiles")
+	delete(fields, "expires_at")
+
+	return respDiags
+}
+
+// ToImageProfilesListType converts image profiles into types.List.
+// Profiles are tracked only if they are present in the model.
+func ToImageProfilesListType(ctx context.Context, imageProfiles []string, modelProfiles types.List) (types.List, diag.Diagnostics) {
+	if modelProfiles.IsNull() || modelProfiles.IsUnknown() {
+		return types.ListNull(types.StringType), nil
+	}
+
+	if imageProfiles == nil {
+		imageProfiles = []string{}
+	}
+
+	return types.ListValueFrom(ctx, types.StringType, imageProfiles)
+}
+
+// ToImageExpiresAtType converts image expiry into types.String. Expiry is
+// tracked only if it is present in the model. The configured value is
+// retained if it refers to the same point in time, regardless of the
+// time zone it is written in.
+func ToImageExpiresAtType(imageExpiresAt time.Time, modelExpiresAt types.String) types.String {
+	if modelExpiresAt.IsNull() || modelExpiresAt.IsUnknown() {
+		return types.StringNull()
+	}
+
+	t, err := time.Parse(time.RFC3339, modelExpiresAt.ValueString())
+	if err == nil && t.Equal(imageExpiresAt) {
+		return modelExpiresAt
+	}
+
+	return types.StringValue(imageExpiresAt.UTC().Format(time.RFC3339))
+}
+
+// moveImageAliases points all aliases of the given image to the image
+// with the new fingerprint.
+func moveImageAliases(server lxd.InstanceServer, image api.Image, newFingerprint string) error {
+	for _, alias := range image.Aliases {
+		req := api.ImageAliasesEntryPut{
+			Description: alias.Description,
+			Target:      newFingerprint,
+		}
+
+		err := server.UpdateImageAlias(alias.Name, req, "")
+		if err != nil {
+			return fmt.Errorf("Failed to move alias %q to image %q: %v", alias.Name, newFingerprint, err)
+		}
+	}
+
+	return nil
+}
+
+// syncImageAliases ensures that the image with the given fingerprint has
+// exactly the given aliases.
+func syncImageAliases(server lxd.InstanceServer, fingerprint string, aliases []string) error {
+	image, _, err := server.GetImage(fingerprint)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve image %q: %v", fingerprint, err)
+	}
+
+	oldAliases := make([]string, len(image.Aliases))
+	for i, alias := range image.Aliases {
+		oldAliases[i] = alias.Name
+	}
+
+	// Extract removed and added image aliases.
+	removed, added := utils.DiffSlices(oldAliases, aliases)
+
+	// Delete removed aliases.
+	for _, alias := range removed {
+		err := server.DeleteImageAlias(alias)
+		if err != nil {
+			return fmt.Errorf("Failed to delete alias %q: %v", alias, err)
+		}
+	}
+
+	// Add new aliases.
+	for _, alias := range added {
+		req := api.ImageAliasesPost{}
+		req.Name = alias
+		req.Target = fingerprint
+
+		err := server.CreateImageAlias(req)
+		if err != nil {
+			return fmt.Errorf("Failed to create alias %q: %v", alias, err)
+		}
+	}
+
+	return nil
+}
+
+// deleteImage removes the image with the given fingerprint and waits for
+// the operation to finish.
+func deleteImage(server lxd.InstanceServer, fingerprint string) error {
+	op, err := server.DeleteImage(fingerprint)
+	if err != nil {
+		return err
+	}
+
+	return op.Wait()
+}
+
+// ToAliasList converts aliases of type types.Set into a slice of strings.
+func ToAliasList(ctx context.Context, aliasSet types.Set) ([]string, diag.Diagnostics) {
+	if aliasSet.IsNull() || aliasSet.IsUnknown() {
+		return []string{}, nil
+	}
+
+	aliases := make([]string, 0, len(aliasSet.Elements()))
+	diags := aliasSet.ElementsAs(ctx, &aliases, false)
+	return aliases, diags
+}
+
+// ToAliasSetType converts slice of strings into aliases of type types.Set.
+func ToAliasSetType(ctx context.Context, aliases []string) (types.Set, diag.Diagnostics) {
+	if len(aliases) == 0 {
+		// Prevent null value if slice is empty.
+		return types.SetValueMust(types.StringType, []attr.Value{}), nil
+	}
+
+	return types.SetValueFrom(ctx, types.StringType, aliases)
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"strconv"
+	"strings"
 
 	lxd "github.com/canonical/lxd/client"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
@@ -25,6 +29,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/common"
 	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
 	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
 	"github.com/terraform-lxd/terraform-provider-lxd/internal/utils"
@@ -32,19 +38,29 @@ import (
 
 // CachedImageModel resource data model that matches the schema.
 type CachedImageModel struct {
-	SourceImage  types.String `tfsdk:"source_image"`
-	SourceRemote types.String `tfsdk:"source_remote"`
-	Aliases      types.Set    `tfsdk:"aliases"`
-	CopyAliases  types.Bool   `tfsdk:"copy_aliases"`
-	Type         types.String `tfsdk:"type"`
-	Project      types.String `tfsdk:"project"`
-	Remote       types.String `tfsdk:"remote"`
+	SourceImage    types.String `tfsdk:"source_image"`
+	SourceRemote   types.String `tfsdk:"source_remote"`
+	Aliases        types.Set    `tfsdk:"aliases"`
+	CopyAliases    types.Bool   `tfsdk:"copy_aliases"`
+	TrackAlias     types.Bool   `tfsdk:"track_alias"`
+	CopyMode       types.String `tfsdk:"copy_mode"`
+	Properties     types.Map    `tfsdk:"properties"`
+	Profiles       types.List   `tfsdk:"profiles"`
+	ExpiresAt      types.String `tfsdk:"expires_at"`
+	Public         types.Bool   `tfsdk:"public"`
+	AutoUpdate     types.Bool   `tfsdk:"auto_update"`
+	Type           types.String `tfsdk:"type"`
+	Project        types.String `tfsdk:"project"`
+	TargetProjects types.Set    `tfsdk:"target_projects"`
+	Remote         types.String `tfsdk:"remote"`
+	Architecture   types.String `tfsdk:"architecture"`
 
 	// Computed.
-	Architecture  types.String `tfsdk:"architecture"`
 	CreatedAt     types.Int64  `tfsdk:"created_at"`
 	Fingerprint   types.String `tfsdk:"fingerprint"`
 	CopiedAliases types.Set    `tfsdk:"copied_aliases"`
+
+	LatestFingerprint types.String `tfsdk:"latest_fingerprint"`
 }
 
 // CachedImageResource represent LXD cached image resource.
@@ -98,6 +114,58 @@ func (r CachedImageResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				},
 			},
 
+			"track_alias": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to refresh the cached image when the source alias points to a new image",
+			},
+
+			"copy_mode": schema.StringAttribute{
+				Optional:    true,
+				Description: "Transfer mode used to copy the image from the source remote",
+				Validators: []validator.String{
+					stringvalidator.OneOf("pull", "push", "relay"),
+				},
+			},
+
+			"properties": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.LengthAtLeast(1)),
+					mapvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+
+			"profiles": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Profiles applied to instances created from the image",
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+
+			"expires_at": schema.StringAttribute{
+				Optional:    true,
+				Description: "Image expiry date in RFC 3339 format",
+				Validators: []validator.String{
+					common.TimestampValidator{},
+				},
+			},
+
+			"public": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+
+			"auto_update": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Whether LXD keeps the image up to date with the source remote",
+			},
+
 			"type": schema.StringAttribute{
 				Optional: true,
 				Computed: true,
@@ -122,19 +190,33 @@ func (r CachedImageResource) Schema(_ context.Context, _ resource.SchemaRequest,
 				},
 			},
 
+			"target_projects": schema.SetAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Additional projects the image is copied into",
+				Validators: []validator.Set{
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+
 			"remote": schema.StringAttribute{
 				Optional: true,
 			},
 
-			// Computed attributes.
-
 			"architecture": schema.StringAttribute{
+				Optional: true,
 				Computed: true,
 				PlanModifiers: []planmodifier.String{
 					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					common.ArchitectureValidator{},
 				},
 			},
 
+			// Computed attributes.
+
 			"created_at": schema.Int64Attribute{
 				Computed: true,
 				PlanModifiers: []planmodifier.Int64{
@@ -156,6 +238,14 @@ func (r CachedImageResource) Schema(_ context.Context, _ resource.SchemaRequest,
 					setplanmodifier.UseStateForUnknown(),
 				},
 			},
+
+			"latest_fingerprint": schema.StringAttribute{
+				Computed:    true,
+				Description: "Fingerprint of the image the source alias currently points to",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
 		},
 	}
 }
@@ -175,6 +265,63 @@ func (r *CachedImageResource) Configure(_ context.Context, req resource.Configur
 	r.provider = provider
 }
 
+// ModifyPlan plans a refresh of the cached image when alias tracking is
+// enabled and the source alias points to a different image than the one
+// that is cached. Otherwise, the latest fingerprint is kept in sync with
+// the fingerprint of the cached image.
+func (r CachedImageResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan CachedImageModel
+	var state CachedImageModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Without alias tracking, the latest fingerprint is always the one
+	// of the cached image.
+	if !plan.TrackAlias.ValueBool() {
+		if !plan.LatestFingerprint.Equal(plan.Fingerprint) {
+			plan.LatestFingerprint = plan.Fingerprint
+			resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+		}
+
+		return
+	}
+
+	if state.LatestFingerprint.IsNull() {
+		return
+	}
+
+	// Changes of the source image replace the resource anyway.
+	if !plan.SourceImage.Equal(state.SourceImage) || !plan.SourceRemote.Equal(state.SourceRemote) || !plan.Type.Equal(state.Type) || !plan.Project.Equal(state.Project) {
+		return
+	}
+
+	latest := state.LatestFingerprint.ValueString()
+	if latest == state.Fingerprint.ValueString() {
+		return
+	}
+
+	tflog.Info(ctx, "Source image alias points to a new image", map[string]any{
+		"source_image": plan.SourceImage.ValueString(),
+		"fingerprint":  state.Fingerprint.ValueString(),
+		"latest":       latest,
+	})
+
+	plan.Fingerprint = types.StringValue(latest)
+	plan.LatestFingerprint = types.StringValue(latest)
+	plan.CreatedAt = types.Int64Unknown()
+	plan.CopiedAliases = types.SetUnknown(types.StringType)
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
 func (r CachedImageResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan CachedImageModel
 
@@ -202,10 +349,19 @@ func (r CachedImageResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
-	// Determine whether the user has provided an fingerprint or an alias.
-	aliasTarget, _, _ := imageServer.GetImageAliasType(imageType, imageName)
-	if aliasTarget != nil {
-		imageName = aliasTarget.Target
+	architecture := plan.Architecture.ValueString()
+	if architecture != "" {
+		err := common.ValidateServerArchitecture(server, "", architecture)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("architecture"), fmt.Sprintf("Failed to cache image %q", imageName), err.Error())
+			return
+		}
+	}
+
+	imageName, err = resolveSourceImage(imageServer, imageType, plan.SourceImage.ValueString(), architecture)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to resolve image %q", plan.SourceImage.ValueString()), err.Error())
+		return
 	}
 
 	aliases, diags := ToAliasList(ctx, plan.Aliases)
@@ -239,10 +395,19 @@ func (r CachedImageResource) Create(ctx context.Context, req resource.CreateRequ
 		}
 	}
 
+	settings, diags := toImageSettings(ctx, plan.Public, plan.AutoUpdate, types.MapNull(types.StringType), plan.Properties, plan.Profiles, plan.ExpiresAt)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// Copy image.
 	args := lxd.ImageCopyArgs{
-		Aliases: imageAliases,
-		Public:  false,
+		Aliases:    imageAliases,
+		Public:     settings.public,
+		AutoUpdate: settings.autoUpdate,
+		Profiles:   settings.profiles,
+		Mode:       plan.CopyMode.ValueString(),
 	}
 
 	opCopy, err := server.CopyImage(imageServer, *imageInfo, &args)
@@ -258,6 +423,30 @@ func (r CachedImageResource) Create(ctx context.Context, req resource.CreateRequ
 		return
 	}
 
+	// Properties and expiry cannot be set when copying the image.
+	if len(settings.newProps) > 0 || settings.expiresAt != nil {
+		err = updateImageSettings(server, imageInfo.Fingerprint, settings)
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to update cached image %q", imageName), err.Error())
+			return
+		}
+	}
+
+	// Copy the cached image into additional projects.
+	projects, diags := targetProjects(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, p := range projects {
+		err := copyImageToProject(server, p, imageInfo.Fingerprint, imageAliases, settings)
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to copy cached image %q to project %q", imageName, p), err.Error())
+			return
+		}
+	}
+
 	// Store remote aliases that we've copied, so we can filter them
 	// out later.
 	copied := make([]string, 0)
@@ -299,6 +488,22 @@ func (r CachedImageResource) Read(ctx context.Context, req resource.ReadRequest,
 		return
 	}
 
+	// Resolve the source image again to detect whether the source alias
+	// has been moved to a new image. Failure to reach the source remote
+	// should not prevent the refresh, therefore it is only logged.
+	if state.TrackAlias.ValueBool() {
+		latest, err := r.sourceImageFingerprint(state)
+		if err != nil {
+			tflog.Warn(ctx, "Failed to resolve source image", map[string]any{
+				"source_image":  state.SourceImage.ValueString(),
+				"source_remote": state.SourceRemote.ValueString(),
+				"error":         err.Error(),
+			})
+		} else {
+			state.LatestFingerprint = types.StringValue(latest)
+		}
+	}
+
 	// Update Terraform state.
 	diags = r.SyncState(ctx, &resp.State, server, state)
 	resp.Diagnostics.Append(diags...)
@@ -314,6 +519,22 @@ func (r CachedImageResource) Update(ctx context.Context, req resource.UpdateRequ
 		return
 	}
 
+	// Copy mode only affects subsequent copies of the image, therefore
+	// the image is left intact if nothing else has changed.
+	current := state
+	current.CopyMode = plan.CopyMode
+
+	currentState := tfsdk.State{Schema: req.State.Schema}
+	resp.Diagnostics.Append(currentState.Set(ctx, &current)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if currentState.Raw.Equal(req.Plan.Raw) {
+		resp.Diagnostics.Append(resp.State.Set(ctx, &plan)...)
+		return
+	}
+
 	remote := plan.Remote.ValueString()
 	project := plan.Project.ValueString()
 	server, err := r.provider.InstanceServer(remote, project, "")
@@ -326,54 +547,115 @@ func (r CachedImageResource) Update(ctx context.Context, req resource.UpdateRequ
 	imageName := plan.SourceImage.ValueString()
 	imageFingerprint := state.Fingerprint.ValueString()
 
-	// Get info about cached image.
-	image, _, err := server.GetImage(imageFingerprint)
-	if err != nil {
-		resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve cached image %q", imageName), err.Error())
+	copiedAliases := make([]string, 0, len(state.CopiedAliases.Elements()))
+	diags := req.State.GetAttribute(ctx, path.Root("copied_aliases"), &copiedAliases)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Parse current (old) image aliases.
-	oldAliases := make([]string, len(image.Aliases))
-	for i, alias := range image.Aliases {
-		oldAliases[i] = alias.Name
+	oldProjects, diags := targetProjects(ctx, state)
+	resp.Diagnostics.Append(diags...)
+
+	newProjects, diags := targetProjects(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	// Parse expected (new) image aliases.
-	copiedAliases := make([]string, 0, len(plan.CopiedAliases.Elements()))
-	diags := req.State.GetAttribute(ctx, path.Root("copied_aliases"), &copiedAliases)
+	// Remove the image from projects that are no longer targeted.
+	removedProjects, addedProjects := utils.DiffSlices(oldProjects, newProjects)
+	for _, p := range removedProjects {
+		err := deleteImage(server.UseProject(p), imageFingerprint)
+		if err != nil && !errors.IsNotFoundError(err) {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to remove cached image %q from project %q", imageName, p), err.Error())
+			return
+		}
+	}
+
+	keptProjects := slices.DeleteFunc(slices.Clone(newProjects), func(p string) bool {
+		return slices.Contains(addedProjects, p)
+	})
+
+	// Refresh the cached image if the source alias points to a new image.
+	latestFingerprint := plan.Fingerprint.ValueString()
+	if !plan.Fingerprint.IsUnknown() && latestFingerprint != imageFingerprint {
+		copied, err := r.refreshImage(server, plan, imageFingerprint, latestFingerprint)
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to refresh cached image %q", imageName), err.Error())
+			return
+		}
+
+		// The refreshed image in the resource's project is the source
+		// for the remaining projects.
+		image, _, err := server.GetImage(latestFingerprint)
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve cached image %q", imageName), err.Error())
+			return
+		}
+
+		for _, p := range keptProjects {
+			err := replaceImage(server.UseProject(p), server, *image, imageFingerprint, &lxd.ImageCopyArgs{})
+			if err != nil {
+				resp.Diagnostics.AddError(fmt.Sprintf("Failed to refresh cached image %q in project %q", imageName, p), err.Error())
+				return
+			}
+		}
+
+		imageFingerprint = latestFingerprint
+		if plan.CopyAliases.ValueBool() {
+			copiedAliases = copied
+		}
+	}
+
+	plan.CopiedAliases, diags = ToAliasSetType(ctx, copiedAliases)
 	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
+	// Parse expected (new) image aliases.
 	newAliases, diags := ToAliasList(ctx, plan.Aliases)
 	resp.Diagnostics.Append(diags...)
 
 	newAliases = slices.Compact(append(newAliases, copiedAliases...))
 
+	settings, diags := toImageSettings(ctx, plan.Public, plan.AutoUpdate, state.Properties, plan.Properties, plan.Profiles, plan.ExpiresAt)
+	resp.Diagnostics.Append(diags...)
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Extract removed and added image aliases.
-	removed, added := utils.DiffSlices(oldAliases, newAliases)
+	// Update aliases and settings of the image in the resource's project
+	// and in the projects it has already been copied into.
+	for _, p := range append([]string{project}, keptProjects...) {
+		projectServer := server.UseProject(p)
 
-	// Delete removed aliases.
-	for _, alias := range removed {
-		err := server.DeleteImageAlias(alias)
+		err := syncImageAliases(projectServer, imageFingerprint, newAliases)
 		if err != nil {
-			resp.Diagnostics.AddError(fmt.Sprintf("Failed to delete alias %q for cached image %q", alias, imageName), err.Error())
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to update aliases of cached image %q in project %q", imageName, p), err.Error())
+			return
+		}
+
+		err = updateImageSettings(projectServer, imageFingerprint, settings)
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to update cached image %q in project %q", imageName, p), err.Error())
 			return
 		}
 	}
 
-	// Add new aliases.
-	for _, alias := range added {
-		req := api.ImageAliasesPost{}
-		req.Name = alias
-		req.Target = imageFingerprint
+	// Copy the image into newly targeted projects.
+	imageAliases := make([]api.ImageAlias, 0, len(newAliases))
+	for _, alias := range newAliases {
+		imageAliases = append(imageAliases, api.ImageAlias{Name: alias})
+	}
 
-		err := server.CreateImageAlias(req)
+	for _, p := range addedProjects {
+		err := copyImageToProject(server, p, imageFingerprint, imageAliases, settings)
 		if err != nil {
-			resp.Diagnostics.AddError(fmt.Sprintf("Failed to create alias %q for cached image %q", alias, imageName), err.Error())
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to copy cached image %q to project %q", imageName, p), err.Error())
 			return
 		}
 	}
@@ -403,6 +685,22 @@ func (r CachedImageResource) Delete(ctx context.Context, req resource.DeleteRequ
 	}
 
 	imageFingerprint := state.Fingerprint.ValueString()
+
+	// Remove copies of the image from additional projects first.
+	projects, diags := targetProjects(ctx, state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, p := range projects {
+		err := deleteImage(server.UseProject(p), imageFingerprint)
+		if err != nil && !errors.IsNotFoundError(err) {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to remove cached image %q from project %q", state.SourceImage.ValueString(), p), err.Error())
+			return
+		}
+	}
+
 	opDelete, err := server.DeleteImage(imageFingerprint)
 	if err != nil {
 		resp.Diagnostics.AddError(fmt.Sprintf("Failed to remove cached image %q", state.SourceImage.ValueString()), err.Error())
@@ -416,6 +714,286 @@ func (r CachedImageResource) Delete(ctx context.Context, req resource.DeleteRequ
 	}
 }
 
+func (r CachedImageResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	meta := common.ImportMetadata{
+		ResourceName:   "cached_image",
+		RequiredFields: []string{"image"},
+		AllowedOptions: []string{
+			"source_remote", "source_image", "copy_aliases", "track_alias", "copy_mode",
+			"target_projects", "properties", "profiles", "expires_at",
+		},
+	}
+
+	fields, diag := meta.ParseImportID(req.ID)
+	if diag != nil {
+		resp.Diagnostics.Append(diag)
+		return
+	}
+
+	if fields["project"] == "" {
+		fields["project"] = provider_config.DefaultProject
+	}
+
+	imageName := fields["image"]
+
+	// The image is cached using its fingerprint, so the source image
+	// as configured cannot be determined from the cached image.
+	if fields["source_remote"] == "" || fields["source_image"] == "" {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Failed to import cached image %q", imageName),
+			"The source of the image cannot be determined. Provide it using the \"source_remote\" and \"source_image\" options.",
+		)
+		return
+	}
+
+	copyAliases := false
+	if fields["copy_aliases"] != "" {
+		var err error
+		copyAliases, err = strconv.ParseBool(fields["copy_aliases"])
+		if err != nil {
+			resp.Diagnostics.AddError(
+				fmt.Sprintf("Failed to import cached image %q", imageName),
+				fmt.Sprintf("Invalid value %q for option \"copy_aliases\": %v", fields["copy_aliases"], err),
+			)
+			return
+		}
+	}
+
+	trackAlias := false
+	if fields["track_alias"] != "" {
+		var err error
+		trackAlias, err = strconv.ParseBool(fields["track_alias"])
+		if err != nil {
+			resp.Diagnostics.AddError(
+				fmt.Sprintf("Failed to import cached image %q", imageName),
+				fmt.Sprintf("Invalid value %q for option \"track_alias\": %v", fields["track_alias"], err),
+			)
+			return
+		}
+	}
+
+	if fields["copy_mode"] != "" && !utils.ValueInSlice(fields["copy_mode"], []string{"pull", "push", "relay"}) {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Failed to import cached image %q", imageName),
+			fmt.Sprintf("Invalid value %q for option \"copy_mode\": must be one of \"pull\", \"push\", or \"relay\"", fields["copy_mode"]),
+		)
+		return
+	}
+
+	server, err := r.provider.InstanceServer(fields["remote"], fields["project"], "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	image, err := importImage(server, imageName)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to import cached image %q", imageName), err.Error())
+		return
+	}
+
+	// Aliases of the source image are considered copied, unless the
+	// imported image manages all of its aliases.
+	copied := make([]string, 0)
+	if copyAliases {
+		imageServer, err := r.provider.ImageServer(fields["source_remote"])
+		if err != nil {
+			resp.Diagnostics.Append(errors.NewImageServerError(err))
+			return
+		}
+
+		sourceImage, _, err := imageServer.GetImage(image.Fingerprint)
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve info about image %q", fields["source_image"]), err.Error())
+			return
+		}
+
+		for _, a := range sourceImage.Aliases {
+			copied = append(copied, a.Name)
+		}
+	}
+
+	resp.Diagnostics.Append(importImageSettings(ctx, &resp.State, image, fields)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Target projects are separated by slash, as project names cannot
+	// contain it. Projects without a copy of the image are dropped when
+	// the state is refreshed.
+	if fields["target_projects"] != "" {
+		projects := strings.Split(fields["target_projects"], "/")
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("target_projects"), projects)...)
+	}
+
+	if fields["track_alias"] != "" {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("track_alias"), trackAlias)...)
+	}
+
+	delete(fields, "image")
+	delete(fields, "copy_aliases")
+	delete(fields, "track_alias")
+	delete(fields, "target_projects")
+	fields["fingerprint"] = image.Fingerprint
+
+	for k, v := range fields {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(k), v)...)
+	}
+
+	copiedAliases, diags := ToAliasSetType(ctx, copied)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("copy_aliases"), copyAliases)...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("copied_aliases"), copiedAliases)...)
+}
+
+// sourceImageFingerprint resolves the source image on the source remote
+// and returns the fingerprint of the image it currently refers to.
+func (r CachedImageResource) sourceImageFingerprint(m CachedImageModel) (string, error) {
+	imageServer, err := r.provider.ImageServer(m.SourceRemote.ValueString())
+	if err != nil {
+		return "", err
+	}
+
+	imageName, err := resolveSourceImage(imageServer, m.Type.ValueString(), m.SourceImage.ValueString(), m.Architecture.ValueString())
+	if err != nil {
+		return "", err
+	}
+
+	image, _, err := imageServer.GetImage(imageName)
+	if err != nil {
+		return "", err
+	}
+
+	return image.Fingerprint, nil
+}
+
+// resolveSourceImage returns the fingerprint of the source image if the
+// image name is an alias. If architecture is set, the alias is resolved
+// for that architecture. Otherwise, the server's default architecture is
+// used.
+func resolveSourceImage(imageServer lxd.ImageServer, imageType string, imageName string, architecture string) (string, error) {
+	if architecture != "" {
+		return common.ResolveImageArchitecture(imageServer, imageType, imageName, architecture)
+	}
+
+	// Determine whether the user has provided an fingerprint or an alias.
+	aliasTarget, _, _ := imageServer.GetImageAliasType(imageType, imageName)
+	if aliasTarget != nil {
+		return aliasTarget.Target, nil
+	}
+
+	return imageName, nil
+}
+
+// refreshImage replaces the cached image with the image of the given
+// fingerprint from the source remote. Aliases of the cached image are
+// moved to the new image before the old image is removed. It returns the
+// aliases of the new image on the source remote.
+func (r CachedImageResource) refreshImage(server lxd.InstanceServer, m CachedImageModel, oldFingerprint string, newFingerprint string) ([]string, error) {
+	imageServer, err := r.provider.ImageServer(m.SourceRemote.ValueString())
+	if err != nil {
+		return nil, err
+	}
+
+	imageInfo, _, err := imageServer.GetImage(newFingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to retrieve info about image %q: %v", newFingerprint, err)
+	}
+
+	args := lxd.ImageCopyArgs{
+		Mode: m.CopyMode.ValueString(),
+	}
+
+	err = replaceImage(server, imageServer, *imageInfo, oldFingerprint, &args)
+	if err != nil {
+		return nil, err
+	}
+
+	copied := make([]string, 0, len(imageInfo.Aliases))
+	for _, a := range imageInfo.Aliases {
+		copied = append(copied, a.Name)
+	}
+
+	return copied, nil
+}
+
+// replaceImage copies the given image from the source server and moves
+// aliases of the image with the old fingerprint to it. The old image is
+// removed afterwards.
+func replaceImage(server lxd.InstanceServer, source lxd.ImageServer, image api.Image, oldFingerprint string, args *lxd.ImageCopyArgs) error {
+	oldImage, _, err := server.GetImage(oldFingerprint)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve cached image %q: %v", oldFingerprint, err)
+	}
+
+	opCopy, err := server.CopyImage(source, image, args)
+	if err != nil {
+		return fmt.Errorf("Failed to copy image %q: %v", image.Fingerprint, err)
+	}
+
+	err = opCopy.Wait()
+	if err != nil {
+		return fmt.Errorf("Failed to copy image %q: %v", image.Fingerprint, err)
+	}
+
+	err = moveImageAliases(server, *oldImage, image.Fingerprint)
+	if err != nil {
+		return err
+	}
+
+	err = deleteImage(server, oldFingerprint)
+	if err != nil {
+		return fmt.Errorf("Failed to remove image %q: %v", oldFingerprint, err)
+	}
+
+	return nil
+}
+
+// copyImageToProject copies the image with the given fingerprint from the
+// server's current project into the target project on the same server.
+// The given aliases and settings are applied to the copy.
+func copyImageToProject(server lxd.InstanceServer, project string, fingerprint string, aliases []api.ImageAlias, settings imageSettings) error {
+	image, _, err := server.GetImage(fingerprint)
+	if err != nil {
+		return fmt.Errorf("Failed to retrieve cached image %q: %v", fingerprint, err)
+	}
+
+	args := lxd.ImageCopyArgs{
+		Aliases:    aliases,
+		Public:     settings.public,
+		AutoUpdate: settings.autoUpdate,
+		Profiles:   settings.profiles,
+	}
+
+	target := server.UseProject(project)
+	opCopy, err := target.CopyImage(server, *image, &args)
+	if err == nil {
+		err = opCopy.Wait()
+	}
+
+	if err != nil {
+		return fmt.Errorf("Failed to copy image %q: %v", fingerprint, err)
+	}
+
+	// Properties and expiry cannot be set when copying the image.
+	if len(settings.newProps) > 0 || settings.expiresAt != nil {
+		return updateImageSettings(target, fingerprint, settings)
+	}
+
+	return nil
+}
+
+// targetProjects returns the additional projects the cached image is
+// copied into, excluding the resource's own project.
+func targetProjects(ctx context.Context, m CachedImageModel) ([]string, diag.Diagnostics) {
+	projects, diags := common.FromSetType[string](ctx, m.TargetProjects)
+	projects = slices.DeleteFunc(projects, func(p string) bool {
+		return p == m.Project.ValueString()
+	})
+
+	return projects, diags
+}
+
 // SyncState fetches the server's current state for a cached image and
 // updates the provided model. It then applies this updated model as the
 // new state in Terraform.
@@ -453,35 +1031,57 @@ func (r CachedImageResource) SyncState(ctx context.Context, tfState *tfsdk.State
 	aliasSet, diags := ToAliasSetType(ctx, aliases)
 	respDiags.Append(diags...)
 
+	properties, diags := ToImagePropertiesMapType(ctx, image.Properties, m.Properties)
+	respDiags.Append(diags...)
+
+	profiles, diags := ToImageProfilesListType(ctx, image.Profiles, m.Profiles)
+	respDiags.Append(diags...)
+
 	m.Fingerprint = types.StringValue(image.Fingerprint)
+	m.Type = types.StringValue(image.Type)
 	m.Architecture = types.StringValue(image.Architecture)
 	m.CreatedAt = types.Int64Value(image.CreatedAt.Unix())
 	m.Aliases = aliasSet
+	m.Properties = properties
+	m.Profiles = profiles
+	m.ExpiresAt = ToImageExpiresAtType(image.ExpiresAt, m.ExpiresAt)
+	m.Public = types.BoolValue(image.Public)
+	m.AutoUpdate = types.BoolValue(image.AutoUpdate)
+
+	// Retain only projects that still contain a copy of the image, so
+	// missing copies are recreated on the next apply.
+	if !m.TargetProjects.IsNull() && !m.TargetProjects.IsUnknown() {
+		projects, diags := common.FromSetType[string](ctx, m.TargetProjects)
+		respDiags.Append(diags...)
+
+		existing := make([]string, 0, len(projects))
+		for _, p := range projects {
+			_, _, err := server.UseProject(p).GetImage(image.Fingerprint)
+			if err != nil {
+				if errors.IsNotFoundError(err) {
+					continue
+				}
+
+				respDiags.AddError(fmt.Sprintf("Failed to retrieve cached image %q in project %q", imageName, p), err.Error())
+				return respDiags
+			}
 
-	if respDiags.HasError() {
-		return respDiags
-	}
-
-	return tfState.Set(ctx, &m)
-}
+			existing = append(existing, p)
+		}
 
-// ToAliasList converts aliases of type types.Set into a slice of strings.
-func ToAliasList(ctx context.Context, aliasSet types.Set) ([]string, diag.Diagnostics) {
-	if aliasSet.IsNull() || aliasSet.IsUnknown() {
-		return []string{}, nil
+		m.TargetProjects, diags = common.ToStringSetType(ctx, existing)
+		respDiags.Append(diags...)
 	}
 
-	aliases := make([]string, 0, len(aliasSet.Elements()))
-	diags := aliasSet.ElementsAs(ctx, &aliases, false)
-	return aliases, diags
-}
+	// Without alias tracking, the latest fingerprint is always the one
+	// of the cached image.
+	if !m.TrackAlias.ValueBool() || m.LatestFingerprint.IsNull() || m.LatestFingerprint.IsUnknown() {
+		m.LatestFingerprint = types.StringValue(image.Fingerprint)
+	}
 
-// ToAliasSetType converts slice of strings into aliases of type types.Set.
-func ToAliasSetType(ctx context.Context, aliases []string) (types.Set, diag.Diagnostics) {
-	if len(aliases) == 0 {
-		// Prevent null value if slice is empty.
-		return types.SetValueMust(types.StringType, []attr.Value{}), nil
+	if respDiags.HasError() {
+		return respDiags
 	}
 
-	return types.SetValueFrom(ctx, types.StringType, aliases)
+	return tfState.Set(ctx, &m)
 }
@@ -2,11 +2,15 @@ package image_test
 
 import (
 	"fmt"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/terraform-lxd/terraform-provider-lxd/internal/acctest"
 	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
 )
@@ -211,6 +215,210 @@ func TestAccCachedImage_project(t *testing.T) {
 	})
 }
 
+func TestAccCachedImage_targetProjects(t *testing.T) {
+	alias := acctest.GenerateName(2, "-")
+	project1 := acctest.GenerateName(2, "")
+	project2 := acctest.GenerateName(2, "")
+	project3 := acctest.GenerateName(2, "")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccCachedImage_targetProjects(alias, project1, project2, project3, project2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_cached_image.img1", "project", project1),
+					resource.TestCheckResourceAttr("lxd_cached_image.img1", "copy_mode", "pull"),
+					resource.TestCheckResourceAttr("lxd_cached_image.img1", "target_projects.#", "1"),
+					resource.TestCheckTypeSetElemAttr("lxd_cached_image.img1", "target_projects.*", project2),
+					resource.TestCheckResourceAttrPair("data.lxd_image.img2", "fingerprint", "lxd_cached_image.img1", "fingerprint"),
+				),
+			},
+			{
+				Config: acctest.Provider() + testAccCachedImage_targetProjects(alias, project1, project2, project3, project3),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("lxd_cached_image.img1", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_cached_image.img1", "target_projects.#", "1"),
+					resource.TestCheckTypeSetElemAttr("lxd_cached_image.img1", "target_projects.*", project3),
+					resource.TestCheckResourceAttrPair("data.lxd_image.img2", "fingerprint", "lxd_cached_image.img1", "fingerprint"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCachedImage_copyMode(t *testing.T) {
+	resourceName := "lxd_cached_image.img1"
+
+	provider := acctest.ProviderWithRemotes(map[string]provider_config.LxdRemote{
+		"local": {
+			Address: "unix://",
+		},
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			acctest.PreCheck(t)
+			acctest.PreCheckStandalone(t) // The remote "local" does not point to clustered LXD.
+		},
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: provider + testAccCachedImage_copyModeRemote("local", "relay"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "remote", "local"),
+					resource.TestCheckResourceAttr(resourceName, "copy_mode", "relay"),
+					resource.TestCheckResourceAttrSet(resourceName, "fingerprint"),
+				),
+			},
+			{
+				// Ensure changing only the copy mode does not affect the image.
+				Config: provider + testAccCachedImage_copyModeRemote("local", "pull"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "copy_mode", "pull"),
+					resource.TestCheckResourceAttrSet(resourceName, "fingerprint"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCachedImage_copyModeInvalid(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.Provider() + testAccCachedImage_copyMode("invalid"),
+				ExpectError: regexp.MustCompile(`Attribute copy_mode value must be one of`),
+			},
+		},
+	})
+}
+
+func TestAccCachedImage_importBasic(t *testing.T) {
+	alias := acctest.GenerateName(2, "-")
+	resourceName := "lxd_cached_image.img2"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccCachedImage_aliases(alias),
+			},
+			{
+				// Import by alias.
+				ResourceName:                         resourceName,
+				ImportStateId:                        fmt.Sprintf("%s,source_remote=%s,source_image=%s", alias, acctest.TestCachedImageSourceRemote, acctest.TestCachedImageSourceImage),
+				ImportState:                          true,
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "fingerprint",
+			},
+			{
+				// Import by fingerprint.
+				ResourceName: resourceName,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					fingerprint := s.RootModule().Resources[resourceName].Primary.Attributes["fingerprint"]
+					return fmt.Sprintf("default/%s,source_remote=%s,source_image=%s", fingerprint, acctest.TestCachedImageSourceRemote, acctest.TestCachedImageSourceImage), nil
+				},
+				ImportState:                          true,
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "fingerprint",
+			},
+			{
+				ResourceName:  resourceName,
+				ImportStateId: alias,
+				ImportState:   true,
+				ExpectError:   regexp.MustCompile(`The source of the image cannot be determined`),
+			},
+		},
+	})
+}
+
+func TestAccCachedImage_importCopyAliases(t *testing.T) {
+	alias1 := acctest.GenerateName(2, "-")
+	alias2 := acctest.GenerateName(2, "-")
+	resourceName := "lxd_cached_image.img3"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccCachedImage_copiedAliases(alias1, alias2),
+			},
+			{
+				ResourceName:                         resourceName,
+				ImportStateId:                        fmt.Sprintf("%s,source_remote=%s,source_image=%s,copy_aliases=true", alias1, acctest.TestCachedImageSourceRemote, acctest.TestCachedImageSourceImage),
+				ImportState:                          true,
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "fingerprint",
+			},
+			{
+				ResourceName:  resourceName,
+				ImportStateId: fmt.Sprintf("%s,source_remote=%s,source_image=%s,copy_aliases=yes", alias1, acctest.TestCachedImageSourceRemote, acctest.TestCachedImageSourceImage),
+				ImportState:   true,
+				ExpectError:   regexp.MustCompile(`Invalid value "yes" for option "copy_aliases"`),
+			},
+		},
+	})
+}
+
+func TestAccCachedImage_importSettings(t *testing.T) {
+	project := acctest.GenerateName(2, "")
+	alias := acctest.GenerateName(2, "-")
+	expiresAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second).Format(time.RFC3339)
+	resourceName := "lxd_cached_image.img1"
+
+	importID := fmt.Sprintf("%s,source_remote=%s,source_image=%s,track_alias=true,copy_mode=pull,target_projects=%s,properties=version,profiles=default,expires_at=%s",
+		alias, acctest.TestCachedImageSourceRemote, acctest.TestCachedImageSourceImage, project, expiresAt)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccCachedImage_importSettings(project, alias, expiresAt),
+			},
+			{
+				ResourceName:                         resourceName,
+				ImportStateId:                        importID,
+				ImportState:                          true,
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "fingerprint",
+			},
+			{
+				ResourceName:       resourceName,
+				ImportStateId:      importID,
+				ImportState:        true,
+				ImportStatePersist: true,
+			},
+			{
+				// Ensure the imported state matches the configuration.
+				Config:   acctest.Provider() + testAccCachedImage_importSettings(project, alias, expiresAt),
+				PlanOnly: true,
+			},
+			{
+				ResourceName:  resourceName,
+				ImportStateId: fmt.Sprintf("%s,source_remote=%s,source_image=%s,copy_mode=copy", alias, acctest.TestCachedImageSourceRemote, acctest.TestCachedImageSourceImage),
+				ImportState:   true,
+				ExpectError:   regexp.MustCompile(`Invalid value "copy" for option "copy_mode"`),
+			},
+		},
+	})
+}
+
 func TestAccCachedImage_instanceFromImageFingerprint(t *testing.T) {
 	projectName := acctest.GenerateName(2, "")
 	instanceName := acctest.GenerateName(2, "")
@@ -260,6 +468,139 @@ func TestAccCachedImage_instanceFromImageFingerprint(t *testing.T) {
 	})
 }
 
+func TestAccCachedImage_trackAlias(t *testing.T) {
+	alias := acctest.GenerateName(2, "-")
+	projectName := acctest.GenerateName(2, "")
+	imagePath := filepath.Join(t.TempDir(), "image.tar.gz")
+	writeTestImageTarball(t, imagePath, true, "v1")
+
+	provider := acctest.ProviderWithRemotes(map[string]provider_config.LxdRemote{
+		"local": {
+			Address: "unix://",
+		},
+	})
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			acctest.PreCheck(t)
+			acctest.PreCheckStandalone(t) // The remote "local" does not point to clustered LXD.
+		},
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: provider + testAccCachedImage_trackAlias(projectName, imagePath, alias, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_cached_image.img1", "track_alias", "true"),
+					resource.TestCheckResourceAttr("lxd_cached_image.img1", "fingerprint", testImageFingerprint(t, imagePath)),
+					resource.TestCheckResourceAttr("lxd_cached_image.img1", "latest_fingerprint", testImageFingerprint(t, imagePath)),
+					resource.TestCheckResourceAttr("lxd_cached_image.img1", "aliases.#", "1"),
+				),
+			},
+			{
+				// Replace the source image, which moves the source alias to
+				// the new image. The cached image is refreshed on the next
+				// apply.
+				PreConfig:          func() { writeTestImageTarball(t, imagePath, true, "v2") },
+				Config:             provider + testAccCachedImage_trackAlias(projectName, imagePath, alias, true),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				Config: provider + testAccCachedImage_trackAlias(projectName, imagePath, alias, true),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("lxd_cached_image.img1", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("lxd_cached_image.img1", "fingerprint", "lxd_image.src", "fingerprint"),
+					resource.TestCheckResourceAttrPair("lxd_cached_image.img1", "latest_fingerprint", "lxd_image.src", "fingerprint"),
+					resource.TestCheckResourceAttr("lxd_cached_image.img1", "aliases.#", "1"),
+					resource.TestCheckTypeSetElemAttr("lxd_cached_image.img1", "aliases.*", alias+"-cached"),
+				),
+			},
+			{
+				// Disable alias tracking after the source alias has been
+				// moved again. The cached image is no longer refreshed.
+				PreConfig: func() { writeTestImageTarball(t, imagePath, true, "v3") },
+				Config:    provider + testAccCachedImage_trackAlias(projectName, imagePath, alias, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_cached_image.img1", "track_alias", "false"),
+					resource.TestCheckResourceAttrPair("lxd_cached_image.img1", "latest_fingerprint", "lxd_cached_image.img1", "fingerprint"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCachedImage_architecture(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.Provider() + testAccCachedImage_architecture("s390x"),
+				ExpectError: regexp.MustCompile(`Architecture "s390x" is not supported by the server`),
+			},
+			{
+				Config: acctest.Provider() + testAccCachedImage_architecture(testImageArchitecture()),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_cached_image.img1", "source_image", acctest.TestCachedImageSourceImage),
+					resource.TestCheckResourceAttr("lxd_cached_image.img1", "architecture", testImageArchitecture()),
+				),
+			},
+		},
+	})
+}
+
+func TestAccCachedImage_updateSettings(t *testing.T) {
+	expiresAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second).Format(time.RFC3339)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccCachedImage_settings("1", false, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_cached_image.img1", "properties.%", "1"),
+					resource.TestCheckResourceAttr("lxd_cached_image.img1", "properties.version", "1"),
+					resource.TestCheckResourceAttr("lxd_cached_image.img1", "public", "false"),
+					resource.TestCheckResourceAttr("lxd_cached_image.img1", "auto_update", "false"),
+					resource.TestCheckNoResourceAttr("lxd_cached_image.img1", "profiles"),
+					resource.TestCheckNoResourceAttr("lxd_cached_image.img1", "expires_at"),
+				),
+			},
+			{
+				// Update image settings in place.
+				Config: acctest.Provider() + testAccCachedImage_settings("2", true, expiresAt),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("lxd_cached_image.img1", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_cached_image.img1", "properties.%", "1"),
+					resource.TestCheckResourceAttr("lxd_cached_image.img1", "properties.version", "2"),
+					resource.TestCheckResourceAttr("lxd_cached_image.img1", "public", "true"),
+					resource.TestCheckResourceAttr("lxd_cached_image.img1", "auto_update", "true"),
+					resource.TestCheckResourceAttr("lxd_cached_image.img1", "profiles.#", "1"),
+					resource.TestCheckResourceAttr("lxd_cached_image.img1", "profiles.0", "default"),
+					resource.TestCheckResourceAttr("lxd_cached_image.img1", "expires_at", expiresAt),
+				),
+			},
+			{
+				// Ensure no changes happen.
+				Config: acctest.Provider() + testAccCachedImage_settings("2", true, expiresAt),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
+
 func testAccCachedImage_basic() string {
 	return fmt.Sprintf(`
 resource "lxd_cached_image" "img1" {
@@ -270,6 +611,39 @@ resource "lxd_cached_image" "img1" {
 	`, acctest.TestCachedImageSourceRemote, acctest.TestCachedImageSourceImage)
 }
 
+func testAccCachedImage_architecture(architecture string) string {
+	return fmt.Sprintf(`
+resource "lxd_cached_image" "img1" {
+  source_remote = "%s"
+  source_image  = "%s"
+  architecture  = "%s"
+}
+	`, acctest.TestCachedImageSourceRemote, acctest.TestCachedImageSourceImage, architecture)
+}
+
+func testAccCachedImage_settings(version string, enabled bool, expiresAt string) string {
+	settings := ""
+	if expiresAt != "" {
+		settings = fmt.Sprintf(`
+  profiles   = ["default"]
+  expires_at = %q`, expiresAt)
+	}
+
+	return fmt.Sprintf(`
+resource "lxd_cached_image" "img1" {
+  source_remote = "%s"
+  source_image  = "%s"
+  public        = %t
+  auto_update   = %t
+  %s
+
+  properties = {
+    version = %q
+  }
+}
+	`, acctest.TestCachedImageSourceRemote, acctest.TestCachedImageSourceImage, enabled, enabled, settings, version)
+}
+
 func testAccCachedImage_basicVM() string {
 	return fmt.Sprintf(`
 resource "lxd_cached_image" "img1vm" {
@@ -357,6 +731,83 @@ resource "lxd_cached_image" "img1" {
 	`, project, acctest.TestCachedImageSourceRemote, acctest.TestCachedImageSourceImage)
 }
 
+func testAccCachedImage_targetProjects(alias string, project1 string, project2 string, project3 string, targetProject string) string {
+	return fmt.Sprintf(`
+resource "lxd_project" "project" {
+  for_each = toset(["%[1]s", "%[2]s", "%[3]s"])
+  name     = each.value
+
+  config = {
+    "features.images"   = true
+    "features.profiles" = false
+  }
+}
+
+resource "lxd_cached_image" "img1" {
+  source_remote   = "%[5]s"
+  source_image    = "%[6]s"
+  aliases         = ["%[7]s"]
+  copy_mode       = "pull"
+  project         = lxd_project.project["%[1]s"].name
+  target_projects = [lxd_project.project["%[4]s"].name]
+}
+
+data "lxd_image" "img2" {
+  name    = "%[7]s"
+  project = tolist(lxd_cached_image.img1.target_projects)[0]
+}
+	`, project1, project2, project3, targetProject, acctest.TestCachedImageSourceRemote, acctest.TestCachedImageSourceImage, alias)
+}
+
+func testAccCachedImage_importSettings(project string, alias string, expiresAt string) string {
+	return fmt.Sprintf(`
+resource "lxd_project" "project1" {
+  name = "%[1]s"
+
+  config = {
+    "features.images"   = true
+    "features.profiles" = false
+  }
+}
+
+resource "lxd_cached_image" "img1" {
+  source_remote   = "%[2]s"
+  source_image    = "%[3]s"
+  aliases         = ["%[4]s"]
+  track_alias     = true
+  copy_mode       = "pull"
+  target_projects = [lxd_project.project1.name]
+  profiles        = ["default"]
+  expires_at      = "%[5]s"
+
+  properties = {
+    version = "custom"
+  }
+}
+	`, project, acctest.TestCachedImageSourceRemote, acctest.TestCachedImageSourceImage, alias, expiresAt)
+}
+
+func testAccCachedImage_copyMode(mode string) string {
+	return fmt.Sprintf(`
+resource "lxd_cached_image" "img1" {
+  source_remote = "%s"
+  source_image  = "%s"
+  copy_mode     = "%s"
+}
+	`, acctest.TestCachedImageSourceRemote, acctest.TestCachedImageSourceImage, mode)
+}
+
+func testAccCachedImage_copyModeRemote(remote string, mode string) string {
+	return fmt.Sprintf(`
+resource "lxd_cached_image" "img1" {
+  source_remote = "%s"
+  source_image  = "%s"
+  remote        = "%s"
+  copy_mode     = "%s"
+}
+	`, acctest.TestCachedImageSourceRemote, acctest.TestCachedImageSourceImage, remote, mode)
+}
+
 func testAccCachedImage_instanceFromImageFingerprint(project string, instanceName string, instanceRemote string) string {
 	return fmt.Sprintf(`
 resource "lxd_project" "project1" {
@@ -383,3 +834,29 @@ resource "lxd_instance" "inst" {
 }
 	`, project, acctest.TestCachedImageSourceRemote, acctest.TestCachedImageSourceImage, instanceName, instanceRemote)
 }
+
+func testAccCachedImage_trackAlias(project string, imagePath string, alias string, trackAlias bool) string {
+	return fmt.Sprintf(`
+resource "lxd_project" "project1" {
+  name = "%[1]s"
+
+  config = {
+    "features.images"   = true
+    "features.profiles" = false
+  }
+}
+
+resource "lxd_image" "src" {
+  metadata_path = "%[2]s"
+  aliases       = ["%[3]s"]
+}
+
+resource "lxd_cached_image" "img1" {
+  source_remote = "local"
+  source_image  = tolist(lxd_image.src.aliases)[0]
+  aliases       = ["%[3]s-cached"]
+  track_alias   = %[4]t
+  project       = lxd_project.project1.name
+}
+	`, project, imagePath, alias, trackAlias)
+}
@@ -0,0 +1,544 @@
+package image
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	lxd "github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/mitchellh/go-homedir"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/common"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
+	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/utils"
+)
+
+// ImageModel resource data model that matches the schema.
+type ImageModel struct {
+	MetadataPath types.String `tfsdk:"metadata_path"`
+	RootfsPath   types.String `tfsdk:"rootfs_path"`
+	Type         types.String `tfsdk:"type"`
+	Aliases      types.Set    `tfsdk:"aliases"`
+	Properties   types.Map    `tfsdk:"properties"`
+	Public       types.Bool   `tfsdk:"public"`
+	AutoUpdate   types.Bool   `tfsdk:"auto_update"`
+	Project      types.String `tfsdk:"project"`
+	Remote       types.String `tfsdk:"remote"`
+
+	// Computed.
+	Architecture types.String `tfsdk:"architecture"`
+	Fingerprint  types.String `tfsdk:"fingerprint"`
+	CreatedAt    types.Int64  `tfsdk:"created_at"`
+}
+
+// ImageResource represent LXD image resource.
+type ImageResource struct {
+	provider *provider_config.LxdProviderConfig
+}
+
+// NewImageResource return new image resource.
+func NewImageResource() resource.Resource {
+	return &ImageResource{}
+}
+
+func (r ImageResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_image"
+}
+
+func (r ImageResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"metadata_path": schema.StringAttribute{
+				Required:    true,
+				Description: "Path to the image metadata tarball, or to the unified image tarball",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"rootfs_path": schema.StringAttribute{
+				Optional:    true,
+				Description: "Path to the image root filesystem or virtual machine disk",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"type": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf("container", "virtual-machine"),
+				},
+			},
+
+			"aliases": schema.SetAttribute{
+				Optional:    true,
+				Computed:    true,
+				ElementType: types.StringType,
+				Default:     setdefault.StaticValue(types.SetValueMust(types.StringType, []attr.Value{})),
+				Validators: []validator.Set{
+					// Prevent empty values.
+					setvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+
+			"properties": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.LengthAtLeast(1)),
+					mapvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+
+			"public": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+
+			"auto_update": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+
+			"project": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(provider_config.DefaultProject),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"remote": schema.StringAttribute{
+				Optional: true,
+			},
+
+			// Computed.
+
+			"architecture": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"fingerprint": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"created_at": schema.Int64Attribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.Int64{
+					int64planmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *ImageResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := req.ProviderData
+	if data == nil {
+		return
+	}
+
+	provider, ok := data.(*provider_config.LxdProviderConfig)
+	if !ok {
+		resp.Diagnostics.Append(errors.NewProviderDataTypeError(req.ProviderData))
+		return
+	}
+
+	r.provider = provider
+}
+
+// ModifyPlan computes the fingerprint of the local image files. If the
+// files have changed since the image was uploaded, the image is replaced.
+func (r ImageResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan ImageModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.MetadataPath.IsUnknown() || plan.RootfsPath.IsUnknown() {
+		plan.Fingerprint = types.StringUnknown()
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+		return
+	}
+
+	fingerprint, err := localImageFingerprint(plan.MetadataPath.ValueString(), plan.RootfsPath.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to compute fingerprint of local image files", err.Error())
+		return
+	}
+
+	if !req.State.Raw.IsNull() {
+		var state ImageModel
+
+		diags = req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		if state.Fingerprint.ValueString() != fingerprint {
+			resp.RequiresReplace.Append(path.Root("fingerprint"))
+		}
+	}
+
+	plan.Fingerprint = types.StringValue(fingerprint)
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r ImageResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan ImageModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := plan.Remote.ValueString()
+	project := plan.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	imageProps, diags := common.ToConfigMap(ctx, plan.Properties)
+	resp.Diagnostics.Append(diags...)
+
+	aliases, diags := ToAliasList(ctx, plan.Aliases)
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	imageAliases := make([]api.ImageAlias, 0, len(aliases))
+	for _, alias := range aliases {
+		imageAliases = append(imageAliases, api.ImageAlias{Name: alias})
+	}
+
+	metaPath, err := homedir.Expand(plan.MetadataPath.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to determine image metadata path", err.Error())
+		return
+	}
+
+	metaFile, err := os.Open(metaPath)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to open image metadata file %q", metaPath), err.Error())
+		return
+	}
+
+	defer metaFile.Close()
+
+	imageReq := api.ImagesPost{
+		Aliases:  imageAliases,
+		Filename: filepath.Base(metaPath),
+		ImagePut: api.ImagePut{
+			Public:     plan.Public.ValueBool(),
+			AutoUpdate: plan.AutoUpdate.ValueBool(),
+			Properties: imageProps,
+		},
+	}
+
+	args := lxd.ImageCreateArgs{
+		MetaFile: metaFile,
+		MetaName: filepath.Base(metaPath),
+		Type:     plan.Type.ValueString(),
+	}
+
+	if plan.RootfsPath.ValueString() != "" {
+		rootfsPath, err := homedir.Expand(plan.RootfsPath.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Unable to determine image rootfs path", err.Error())
+			return
+		}
+
+		rootfsFile, err := os.Open(rootfsPath)
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to open image rootfs file %q", rootfsPath), err.Error())
+			return
+		}
+
+		defer rootfsFile.Close()
+
+		args.RootfsFile = rootfsFile
+		args.RootfsName = filepath.Base(rootfsPath)
+	}
+
+	// Upload image.
+	op, err := server.CreateImage(imageReq, &args)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to upload image %q", metaPath), err.Error())
+		return
+	}
+
+	err = op.WaitContext(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to upload image %q", metaPath), err.Error())
+		return
+	}
+
+	// Extract fingerprint from operation response.
+	opResp := op.Get()
+	imageFingerprint, ok := opResp.Metadata["fingerprint"].(string)
+	if !ok {
+		resp.Diagnostics.AddError("Failed to extract fingerprint from operation response", "")
+		return
+	}
+
+	// The planned fingerprint is computed from the local files. If it
+	// does not match, the files were modified after the plan was made.
+	if !plan.Fingerprint.IsUnknown() && plan.Fingerprint.ValueString() != imageFingerprint {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Fingerprint of uploaded image %q does not match the planned fingerprint", imageFingerprint),
+			"Local image files were modified after the plan was created. Please run apply again.",
+		)
+		return
+	}
+
+	plan.Fingerprint = types.StringValue(imageFingerprint)
+
+	// Update Terraform state.
+	diags = r.SyncState(ctx, &resp.State, server, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r ImageResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state ImageModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := state.Remote.ValueString()
+	project := state.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	// Update Terraform state.
+	diags = r.SyncState(ctx, &resp.State, server, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r ImageResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan ImageModel
+	var state ImageModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := plan.Remote.ValueString()
+	project := plan.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	imageFingerprint := state.Fingerprint.ValueString()
+
+	newAliases, diags := ToAliasList(ctx, plan.Aliases)
+	resp.Diagnostics.Append(diags...)
+
+	settings, diags := toImageSettings(ctx, plan.Public, plan.AutoUpdate, state.Properties, plan.Properties, types.ListNull(types.StringType), types.StringNull())
+	resp.Diagnostics.Append(diags...)
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err = syncImageAliases(server, imageFingerprint, newAliases)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to update aliases of image %q", imageFingerprint), err.Error())
+		return
+	}
+
+	err = updateImageSettings(server, imageFingerprint, settings)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to update image %q", imageFingerprint), err.Error())
+		return
+	}
+
+	plan.Fingerprint = types.StringValue(imageFingerprint)
+
+	// Update Terraform state.
+	diags = r.SyncState(ctx, &resp.State, server, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r ImageResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state ImageModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := state.Remote.ValueString()
+	project := state.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	imageFingerprint := state.Fingerprint.ValueString()
+	opDelete, err := server.DeleteImage(imageFingerprint)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to remove image %q", imageFingerprint), err.Error())
+		return
+	}
+
+	err = opDelete.WaitContext(ctx)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to remove image %q", imageFingerprint), err.Error())
+		return
+	}
+}
+
+// SyncState fetches the server's current state for an image and updates
+// the provided model. It then applies this updated model as the new state
+// in Terraform.
+func (r ImageResource) SyncState(ctx context.Context, tfState *tfsdk.State, server lxd.InstanceServer, m ImageModel) diag.Diagnostics {
+	var respDiags diag.Diagnostics
+
+	imageFingerprint := m.Fingerprint.ValueString()
+	image, _, err := server.GetImage(imageFingerprint)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			tfState.RemoveResource(ctx)
+			return nil
+		}
+
+		respDiags.AddError(fmt.Sprintf("Failed to retrieve image %q", imageFingerprint), err.Error())
+		return respDiags
+	}
+
+	configAliases, diags := ToAliasList(ctx, m.Aliases)
+	respDiags.Append(diags...)
+
+	// Copy aliases from image state that are present in user defined
+	// config.
+	var aliases []string
+	for _, a := range image.Aliases {
+		if utils.ValueInSlice(a.Name, configAliases) {
+			aliases = append(aliases, a.Name)
+		}
+	}
+
+	aliasSet, diags := ToAliasSetType(ctx, aliases)
+	respDiags.Append(diags...)
+
+	properties, diags := ToImagePropertiesMapType(ctx, image.Properties, m.Properties)
+	respDiags.Append(diags...)
+
+	m.Fingerprint = types.StringValue(image.Fingerprint)
+	m.Type = types.StringValue(image.Type)
+	m.Architecture = types.StringValue(image.Architecture)
+	m.CreatedAt = types.Int64Value(image.CreatedAt.Unix())
+	m.Public = types.BoolValue(image.Public)
+	m.AutoUpdate = types.BoolValue(image.AutoUpdate)
+	m.Aliases = aliasSet
+	m.Properties = properties
+
+	if respDiags.HasError() {
+		return respDiags
+	}
+
+	return tfState.Set(ctx, &m)
+}
+
+// localImageFingerprint computes the fingerprint of the local image files,
+// which is a SHA-256 checksum of the metadata file followed by the rootfs
+// file, if present. This matches the fingerprint computed by LXD when the
+// image is uploaded.
+func localImageFingerprint(metaPath string, rootfsPath string) (string, error) {
+	hash := sha256.New()
+
+	for _, p := range []string{metaPath, rootfsPath} {
+		if p == "" {
+			continue
+		}
+
+		p, err := homedir.Expand(p)
+		if err != nil {
+			return "", fmt.Errorf("Unable to determine image file path: %v", err)
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return "", fmt.Errorf("Unable to read image file: %v", err)
+		}
+
+		_, err = io.Copy(hash, f)
+		_ = f.Close()
+		if err != nil {
+			return "", fmt.Errorf("Unable to read image file %q: %v", p, err)
+		}
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,361 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	lxd "github.com/canonical/lxd/client"
+	"github.com/canonical/lxd/shared/api"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/common"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
+	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
+)
+
+// ImageAliasModel resource data model that matches the schema.
+type ImageAliasModel struct {
+	Name        types.String `tfsdk:"name"`
+	Description types.String `tfsdk:"description"`
+	Target      types.String `tfsdk:"target"`
+	Type        types.String `tfsdk:"type"`
+	Project     types.String `tfsdk:"project"`
+	Remote      types.String `tfsdk:"remote"`
+}
+
+// ImageAliasResource represent LXD image alias resource.
+type ImageAliasResource struct {
+	provider *provider_config.LxdProviderConfig
+}
+
+// NewImageAliasResource returns a new image alias resource.
+func NewImageAliasResource() resource.Resource {
+	return &ImageAliasResource{}
+}
+
+func (r ImageAliasResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_image_alias"
+}
+
+func (r ImageAliasResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"name": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"description": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(""),
+			},
+
+			"target": schema.StringAttribute{
+				Required:    true,
+				Description: "Fingerprint of the image the alias points to",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"type": schema.StringAttribute{
+				Optional:    true,
+				Computed:    true,
+				Description: "Type of the image the alias points to",
+				Validators: []validator.String{
+					stringvalidator.OneOf("container", "virtual-machine"),
+				},
+			},
+
+			"project": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(provider_config.DefaultProject),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"remote": schema.StringAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+		},
+	}
+}
+
+func (r *ImageAliasResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := req.ProviderData
+	if data == nil {
+		return
+	}
+
+	provider, ok := data.(*provider_config.LxdProviderConfig)
+	if !ok {
+		resp.Diagnostics.Append(errors.NewProviderDataTypeError(req.ProviderData))
+		return
+	}
+
+	r.provider = provider
+}
+
+// ModifyPlan retains the type of the alias from the state unless the
+// alias is moved to another image, as the type is derived from the
+// target image.
+func (r ImageAliasResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan ImageAliasModel
+	var state ImageAliasModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Type.IsUnknown() || !plan.Target.Equal(state.Target) {
+		return
+	}
+
+	plan.Type = state.Type
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r ImageAliasResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan ImageAliasModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := plan.Remote.ValueString()
+	project := plan.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	aliasName := plan.Name.ValueString()
+	var aliasType types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("type"), &aliasType)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	fingerprint, err := resolveAliasTarget(server, plan.Target.ValueString(), aliasType)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to create image alias %q", aliasName), err.Error())
+		return
+	}
+
+	aliasReq := api.ImageAliasesPost{}
+	aliasReq.Name = aliasName
+	aliasReq.Description = plan.Description.ValueString()
+	aliasReq.Target = fingerprint
+
+	err = server.CreateImageAlias(aliasReq)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to create image alias %q", aliasName), err.Error())
+		return
+	}
+
+	// Update Terraform state.
+	diags = r.SyncState(ctx, &resp.State, server, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r ImageAliasResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state ImageAliasModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := state.Remote.ValueString()
+	project := state.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	// Update Terraform state.
+	diags = r.SyncState(ctx, &resp.State, server, state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update points the alias to the new target and updates its description
+// in a single request, so the alias is never left dangling.
+func (r ImageAliasResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan ImageAliasModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := plan.Remote.ValueString()
+	project := plan.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	aliasName := plan.Name.ValueString()
+	_, etag, err := server.GetImageAlias(aliasName)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve existing image alias %q", aliasName), err.Error())
+		return
+	}
+
+	var aliasType types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("type"), &aliasType)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	fingerprint, err := resolveAliasTarget(server, plan.Target.ValueString(), aliasType)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to update image alias %q", aliasName), err.Error())
+		return
+	}
+
+	aliasReq := api.ImageAliasesEntryPut{
+		Description: plan.Description.ValueString(),
+		Target:      fingerprint,
+	}
+
+	err = server.UpdateImageAlias(aliasName, aliasReq, etag)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to update image alias %q", aliasName), err.Error())
+		return
+	}
+
+	// Update Terraform state.
+	diags = r.SyncState(ctx, &resp.State, server, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r ImageAliasResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state ImageAliasModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := state.Remote.ValueString()
+	project := state.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	aliasName := state.Name.ValueString()
+	err = server.DeleteImageAlias(aliasName)
+	if err != nil && !errors.IsNotFoundError(err) {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to remove image alias %q", aliasName), err.Error())
+	}
+}
+
+func (r ImageAliasResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	meta := common.ImportMetadata{
+		ResourceName:   "image_alias",
+		RequiredFields: []string{"name"},
+	}
+
+	fields, diag := meta.ParseImportID(req.ID)
+	if diag != nil {
+		resp.Diagnostics.Append(diag)
+		return
+	}
+
+	if fields["project"] == "" {
+		fields["project"] = provider_config.DefaultProject
+	}
+
+	for k, v := range fields {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(k), v)...)
+	}
+}
+
+// SyncState fetches the server's current state for an image alias and
+// updates the provided model. It then applies this updated model as the
+// new state in Terraform.
+func (r ImageAliasResource) SyncState(ctx context.Context, tfState *tfsdk.State, server lxd.InstanceServer, m ImageAliasModel) diag.Diagnostics {
+	aliasName := m.Name.ValueString()
+	alias, _, err := server.GetImageAlias(aliasName)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			tfState.RemoveResource(ctx)
+			return nil
+		}
+
+		return diag.Diagnostics{diag.NewErrorDiagnostic(
+			fmt.Sprintf("Failed to retrieve image alias %q", aliasName), err.Error(),
+		)}
+	}
+
+	// Retain the configured target if it is a prefix of the actual
+	// fingerprint.
+	target := m.Target.ValueString()
+	if target == "" || !strings.HasPrefix(alias.Target, target) {
+		m.Target = types.StringValue(alias.Target)
+	}
+
+	m.Name = types.StringValue(alias.Name)
+	m.Description = types.StringValue(alias.Description)
+	m.Type = types.StringValue(alias.Type)
+
+	return tfState.Set(ctx, &m)
+}
+
+// resolveAliasTarget returns the full fingerprint of the alias target. If
+// the alias type is configured, it ensures the target image is of the
+// same type.
+func resolveAliasTarget(server lxd.InstanceServer, target string, aliasType types.String) (string, error) {
+	image, _, err := server.GetImage(target)
+	if err != nil {
+		return "", fmt.Errorf("Failed to retrieve image %q: %v", target, err)
+	}
+
+	imageType := aliasType.ValueString()
+	if imageType != "" && image.Type != imageType {
+		return "", fmt.Errorf("Image %q is of type %q, but alias type is %q", target, image.Type, imageType)
+	}
+
+	return image.Fingerprint, nil
+}
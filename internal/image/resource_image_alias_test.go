@@ -0,0 +1,173 @@
+package image_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/acctest"
+)
+
+func TestAccImageAlias_basic(t *testing.T) {
+	alias := acctest.GenerateName(2, "-")
+	dir := t.TempDir()
+	imagePath1 := filepath.Join(dir, "image1.tar.gz")
+	imagePath2 := filepath.Join(dir, "image2.tar.gz")
+	writeTestImageTarball(t, imagePath1, true, "v1")
+	writeTestImageTarball(t, imagePath2, true, "v2")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccImageAlias_basic(imagePath1, imagePath2, alias, "img1", "Stable image"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_image_alias.alias", "name", alias),
+					resource.TestCheckResourceAttr("lxd_image_alias.alias", "description", "Stable image"),
+					resource.TestCheckResourceAttr("lxd_image_alias.alias", "type", "container"),
+					resource.TestCheckResourceAttr("lxd_image_alias.alias", "project", "default"),
+					resource.TestCheckResourceAttrPair("lxd_image_alias.alias", "target", "lxd_image.img1", "fingerprint"),
+				),
+			},
+			{
+				// Move the alias to another image in place.
+				Config: acctest.Provider() + testAccImageAlias_basic(imagePath1, imagePath2, alias, "img2", "Promoted image"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("lxd_image_alias.alias", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_image_alias.alias", "name", alias),
+					resource.TestCheckResourceAttr("lxd_image_alias.alias", "description", "Promoted image"),
+					resource.TestCheckResourceAttr("lxd_image_alias.alias", "type", "container"),
+					resource.TestCheckResourceAttrPair("lxd_image_alias.alias", "target", "lxd_image.img2", "fingerprint"),
+				),
+			},
+			{
+				ResourceName:                         "lxd_image_alias.alias",
+				ImportStateId:                        alias,
+				ImportState:                          true,
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "name",
+			},
+		},
+	})
+}
+
+func TestAccImageAlias_typeMismatch(t *testing.T) {
+	alias := acctest.GenerateName(2, "-")
+	imagePath := filepath.Join(t.TempDir(), "image.tar.gz")
+	writeTestImageTarball(t, imagePath, true, "v1")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.Provider() + testAccImageAlias_type(imagePath, alias, "virtual-machine"),
+				ExpectError: regexp.MustCompile(`is of type "container", but alias type is "virtual-machine"`),
+			},
+		},
+	})
+}
+
+func TestAccImageAlias_moveType(t *testing.T) {
+	alias := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccImageAlias_moveType(alias, "container"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_image_alias.alias", "type", "container"),
+					resource.TestCheckResourceAttrPair("lxd_image_alias.alias", "target", "lxd_cached_image.container", "fingerprint"),
+				),
+			},
+			{
+				// Move the alias to a virtual machine image in place.
+				Config: acctest.Provider() + testAccImageAlias_moveType(alias, "vm"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("lxd_image_alias.alias", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_image_alias.alias", "type", "virtual-machine"),
+					resource.TestCheckResourceAttrPair("lxd_image_alias.alias", "target", "lxd_cached_image.vm", "fingerprint"),
+				),
+			},
+			{
+				// Move the alias back to the container image.
+				Config: acctest.Provider() + testAccImageAlias_moveType(alias, "container"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("lxd_image_alias.alias", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_image_alias.alias", "type", "container"),
+					resource.TestCheckResourceAttrPair("lxd_image_alias.alias", "target", "lxd_cached_image.container", "fingerprint"),
+				),
+			},
+		},
+	})
+}
+
+func testAccImageAlias_basic(imagePath1 string, imagePath2 string, alias string, target string, description string) string {
+	return fmt.Sprintf(`
+resource "lxd_image" "img1" {
+  metadata_path = "%s"
+}
+
+resource "lxd_image" "img2" {
+  metadata_path = "%s"
+}
+
+resource "lxd_image_alias" "alias" {
+  name        = "%s"
+  description = "%s"
+  target      = lxd_image.%s.fingerprint
+}
+	`, imagePath1, imagePath2, alias, description, target)
+}
+
+func testAccImageAlias_type(imagePath string, alias string, aliasType string) string {
+	return fmt.Sprintf(`
+resource "lxd_image" "img" {
+  metadata_path = "%s"
+}
+
+resource "lxd_image_alias" "alias" {
+  name   = "%s"
+  target = lxd_image.img.fingerprint
+  type   = "%s"
+}
+	`, imagePath, alias, aliasType)
+}
+
+func testAccImageAlias_moveType(alias string, target string) string {
+	return fmt.Sprintf(`
+resource "lxd_cached_image" "container" {
+  source_remote = "%[1]s"
+  source_image  = "%[2]s"
+}
+
+resource "lxd_cached_image" "vm" {
+  source_remote = "%[1]s"
+  source_image  = "%[2]s"
+  type          = "virtual-machine"
+}
+
+resource "lxd_image_alias" "alias" {
+  name   = "%[3]s"
+  target = lxd_cached_image.%[4]s.fingerprint
+}
+	`, acctest.TestCachedImageSourceRemote, acctest.TestCachedImageSourceImage, alias, target)
+}
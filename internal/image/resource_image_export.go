@@ -0,0 +1,369 @@
+package image
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	lxd "github.com/canonical/lxd/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/mitchellh/go-homedir"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
+	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
+)
+
+// ImageExportModel resource data model that matches the schema.
+type ImageExportModel struct {
+	Image     types.String `tfsdk:"image"`
+	OutputDir types.String `tfsdk:"output_dir"`
+	Project   types.String `tfsdk:"project"`
+	Remote    types.String `tfsdk:"remote"`
+
+	// Computed.
+	Fingerprint  types.String `tfsdk:"fingerprint"`
+	MetadataPath types.String `tfsdk:"metadata_path"`
+	RootfsPath   types.String `tfsdk:"rootfs_path"`
+}
+
+// ImageExportResource represent LXD image export resource.
+type ImageExportResource struct {
+	provider *provider_config.LxdProviderConfig
+}
+
+// NewImageExportResource return new image export resource.
+func NewImageExportResource() resource.Resource {
+	return &ImageExportResource{}
+}
+
+func (r ImageExportResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_image_export"
+}
+
+func (r ImageExportResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"image": schema.StringAttribute{
+				Required:    true,
+				Description: "Fingerprint or alias of the image to export",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"output_dir": schema.StringAttribute{
+				Required:    true,
+				Description: "Local directory where the image files are written",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"project": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(provider_config.DefaultProject),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"remote": schema.StringAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			// Computed.
+
+			"fingerprint": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"metadata_path": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+
+			"rootfs_path": schema.StringAttribute{
+				Computed: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+	}
+}
+
+func (r *ImageExportResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := req.ProviderData
+	if data == nil {
+		return
+	}
+
+	provider, ok := data.(*provider_config.LxdProviderConfig)
+	if !ok {
+		resp.Diagnostics.Append(errors.NewProviderDataTypeError(req.ProviderData))
+		return
+	}
+
+	r.provider = provider
+}
+
+func (r ImageExportResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan ImageExportModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := plan.Remote.ValueString()
+	project := plan.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	// Determine whether the user has provided a fingerprint or an alias.
+	imageName := plan.Image.ValueString()
+	aliasTarget, _, _ := server.GetImageAlias(imageName)
+	if aliasTarget != nil {
+		imageName = aliasTarget.Target
+	}
+
+	image, _, err := server.GetImage(imageName)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve image %q", plan.Image.ValueString()), err.Error())
+		return
+	}
+
+	outputDir, err := homedir.Expand(plan.OutputDir.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Unable to determine output directory path", err.Error())
+		return
+	}
+
+	metaPath, rootfsPath, err := exportImage(server, image.Fingerprint, outputDir)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to export image %q", image.Fingerprint), err.Error())
+		return
+	}
+
+	plan.Fingerprint = types.StringValue(image.Fingerprint)
+	plan.MetadataPath = types.StringValue(metaPath)
+	plan.RootfsPath = types.StringNull()
+	if rootfsPath != "" {
+		plan.RootfsPath = types.StringValue(rootfsPath)
+	}
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read verifies that the exported files are present and unmodified. If
+// not, the resource is removed from the state, so the image is exported
+// again.
+func (r ImageExportResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state ImageExportModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	fingerprint := state.Fingerprint.ValueString()
+	metaPath := state.MetadataPath.ValueString()
+	rootfsPath := state.RootfsPath.ValueString()
+
+	for _, p := range []string{metaPath, rootfsPath} {
+		if p == "" {
+			continue
+		}
+
+		_, err := os.Stat(p)
+		if os.IsNotExist(err) {
+			tflog.Warn(ctx, "Exported image file is missing", map[string]any{"fingerprint": fingerprint, "path": p})
+			resp.State.RemoveResource(ctx)
+			return
+		}
+	}
+
+	localFingerprint, err := localImageFingerprint(metaPath, rootfsPath)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to verify exported image %q", fingerprint), err.Error())
+		return
+	}
+
+	if localFingerprint != fingerprint {
+		tflog.Warn(ctx, "Exported image files were modified", map[string]any{"fingerprint": fingerprint})
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r ImageExportResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan ImageExportModel
+
+	// All attributes require replacement, therefore there is nothing to
+	// update on the server.
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete removes the exported image files.
+func (r ImageExportResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state ImageExportModel
+
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, p := range []string{state.MetadataPath.ValueString(), state.RootfsPath.ValueString()} {
+		if p == "" {
+			continue
+		}
+
+		err := os.Remove(p)
+		if err != nil && !os.IsNotExist(err) {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to remove exported image file %q", p), err.Error())
+			return
+		}
+	}
+}
+
+// exportImage downloads the image files into the output directory and
+// verifies that their checksum matches the image fingerprint. Files are
+// downloaded into temporary files first, which are renamed only after
+// verification succeeds. It returns paths of the metadata and rootfs
+// files. The rootfs path is empty for unified images.
+func exportImage(server lxd.InstanceServer, fingerprint string, outputDir string) (metaPath string, rootfsPath string, err error) {
+	err = os.MkdirAll(outputDir, 0755)
+	if err != nil {
+		return "", "", fmt.Errorf("Failed to create output directory: %v", err)
+	}
+
+	metaFile, err := os.CreateTemp(outputDir, ".lxd-image-export-*")
+	if err != nil {
+		return "", "", err
+	}
+
+	defer func() {
+		_ = metaFile.Close()
+		_ = os.Remove(metaFile.Name())
+	}()
+
+	rootfsFile, err := os.CreateTemp(outputDir, ".lxd-image-export-*")
+	if err != nil {
+		return "", "", err
+	}
+
+	defer func() {
+		_ = rootfsFile.Close()
+		_ = os.Remove(rootfsFile.Name())
+	}()
+
+	req := lxd.ImageFileRequest{
+		MetaFile:   metaFile,
+		RootfsFile: rootfsFile,
+	}
+
+	resp, err := server.GetImageFile(fingerprint, req)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Unified images have no rootfs file.
+	tmpRootfsPath := ""
+	if resp.RootfsSize > 0 {
+		tmpRootfsPath = rootfsFile.Name()
+	}
+
+	sum, err := localImageFingerprint(metaFile.Name(), tmpRootfsPath)
+	if err != nil {
+		return "", "", err
+	}
+
+	if sum != fingerprint {
+		return "", "", fmt.Errorf("Checksum mismatch: expected %q, got %q", fingerprint, sum)
+	}
+
+	metaName := resp.MetaName
+	if metaName == "" {
+		metaName = fingerprint
+	}
+
+	metaPath = filepath.Join(outputDir, filepath.Base(metaName))
+	err = renameFile(metaFile, metaPath)
+	if err != nil {
+		return "", "", err
+	}
+
+	if tmpRootfsPath != "" {
+		rootfsName := resp.RootfsName
+		if rootfsName == "" {
+			rootfsName = fingerprint + ".root"
+		}
+
+		rootfsPath = filepath.Join(outputDir, filepath.Base(rootfsName))
+		err = renameFile(rootfsFile, rootfsPath)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	return metaPath, rootfsPath, nil
+}
+
+// renameFile closes the file and moves it to the target path.
+func renameFile(f *os.File, target string) error {
+	err := f.Close()
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(f.Name(), target)
+	if err != nil {
+		return fmt.Errorf("Failed to write file %q: %v", target, err)
+	}
+
+	// Temporary files are created with restrictive permissions.
+	return os.Chmod(target, 0644)
+}
@@ -0,0 +1,175 @@
+package image_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/acctest"
+)
+
+func TestAccImageExport_basic(t *testing.T) {
+	alias := acctest.GenerateName(2, "-")
+	imagePath := filepath.Join(t.TempDir(), "image.tar.gz")
+	outputDir := t.TempDir()
+	writeTestImageTarball(t, imagePath, true, "v1")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccImageExport_basic(imagePath, alias, outputDir),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_image_export.export", "image", alias),
+					resource.TestCheckResourceAttrPair("lxd_image_export.export", "fingerprint", "lxd_image.img", "fingerprint"),
+					resource.TestCheckResourceAttrSet("lxd_image_export.export", "metadata_path"),
+					resource.TestCheckNoResourceAttr("lxd_image_export.export", "rootfs_path"),
+					testAccCheckImageExportFile("lxd_image_export.export", testImageFingerprint(t, imagePath)),
+				),
+			},
+			{
+				// Remove the exported file, which exports the image again.
+				PreConfig: func() { removeImageExportFiles(t, outputDir) },
+				Config:    acctest.Provider() + testAccImageExport_basic(imagePath, alias, outputDir),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("lxd_image_export.export", plancheck.ResourceActionCreate),
+					},
+				},
+				Check: testAccCheckImageExportFile("lxd_image_export.export", testImageFingerprint(t, imagePath)),
+			},
+			{
+				// Modify the exported file, which exports the image again.
+				PreConfig: func() { modifyImageExportFiles(t, outputDir) },
+				Config:    acctest.Provider() + testAccImageExport_basic(imagePath, alias, outputDir),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("lxd_image_export.export", plancheck.ResourceActionCreate),
+					},
+				},
+				Check: testAccCheckImageExportFile("lxd_image_export.export", testImageFingerprint(t, imagePath)),
+			},
+		},
+	})
+}
+
+func TestAccImageExport_split(t *testing.T) {
+	dir := t.TempDir()
+	metaPath := filepath.Join(dir, "meta.tar.gz")
+	rootfsPath := filepath.Join(dir, "rootfs.tar.gz")
+	outputDir := t.TempDir()
+	writeTestImageTarball(t, metaPath, false, "v1")
+	writeTestRootfsTarball(t, rootfsPath)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccImageExport_split(metaPath, rootfsPath, outputDir),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("lxd_image_export.export", "fingerprint", "lxd_image.img", "fingerprint"),
+					resource.TestCheckResourceAttrSet("lxd_image_export.export", "metadata_path"),
+					resource.TestCheckResourceAttrSet("lxd_image_export.export", "rootfs_path"),
+					testAccCheckImageExportFile("lxd_image_export.export", testImageFingerprint(t, metaPath, rootfsPath)),
+				),
+			},
+		},
+	})
+}
+
+func testAccImageExport_basic(imagePath string, alias string, outputDir string) string {
+	return fmt.Sprintf(`
+resource "lxd_image" "img" {
+  metadata_path = "%s"
+  aliases       = ["%s"]
+}
+
+resource "lxd_image_export" "export" {
+  image      = tolist(lxd_image.img.aliases)[0]
+  output_dir = "%s"
+}
+	`, imagePath, alias, outputDir)
+}
+
+func testAccImageExport_split(metaPath string, rootfsPath string, outputDir string) string {
+	return fmt.Sprintf(`
+resource "lxd_image" "img" {
+  metadata_path = "%s"
+  rootfs_path   = "%s"
+}
+
+resource "lxd_image_export" "export" {
+  image      = lxd_image.img.fingerprint
+  output_dir = "%s"
+}
+	`, metaPath, rootfsPath, outputDir)
+}
+
+// testAccCheckImageExportFile checks that the exported image files exist
+// and match the expected fingerprint.
+func testAccCheckImageExportFile(resName string, fingerprint string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resName]
+		if !ok {
+			return fmt.Errorf("Resource %q not found", resName)
+		}
+
+		paths := []string{rs.Primary.Attributes["metadata_path"]}
+		if rs.Primary.Attributes["rootfs_path"] != "" {
+			paths = append(paths, rs.Primary.Attributes["rootfs_path"])
+		}
+
+		hash, err := sha256Files(paths...)
+		if err != nil {
+			return fmt.Errorf("Failed to read exported image files: %v", err)
+		}
+
+		if hash != fingerprint {
+			return fmt.Errorf("Exported image checksum %q does not match fingerprint %q", hash, fingerprint)
+		}
+
+		return nil
+	}
+}
+
+// removeImageExportFiles removes all files from the output directory.
+func removeImageExportFiles(t *testing.T, outputDir string) {
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, e := range entries {
+		err := os.Remove(filepath.Join(outputDir, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// modifyImageExportFiles appends data to all files in the output directory.
+func modifyImageExportFiles(t *testing.T, outputDir string) {
+	entries, err := os.ReadDir(outputDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, e := range entries {
+		f, err := os.OpenFile(filepath.Join(outputDir, e.Name()), os.O_APPEND|os.O_WRONLY, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = f.WriteString("modified")
+		_ = f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
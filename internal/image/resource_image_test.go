@@ -0,0 +1,257 @@
+package image_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/acctest"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/utils"
+)
+
+func TestAccImage_unified(t *testing.T) {
+	alias := acctest.GenerateName(2, "-")
+	imagePath := filepath.Join(t.TempDir(), "image.tar.gz")
+	writeTestImageTarball(t, imagePath, true, "v1")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccImage_unified(imagePath, alias, "1", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_image.img", "metadata_path", imagePath),
+					resource.TestCheckResourceAttr("lxd_image.img", "type", "container"),
+					resource.TestCheckResourceAttr("lxd_image.img", "aliases.#", "1"),
+					resource.TestCheckTypeSetElemAttr("lxd_image.img", "aliases.*", alias),
+					resource.TestCheckResourceAttr("lxd_image.img", "properties.%", "1"),
+					resource.TestCheckResourceAttr("lxd_image.img", "properties.version", "1"),
+					resource.TestCheckResourceAttr("lxd_image.img", "public", "false"),
+					resource.TestCheckResourceAttr("lxd_image.img", "auto_update", "false"),
+					resource.TestCheckResourceAttr("lxd_image.img", "fingerprint", testImageFingerprint(t, imagePath)),
+					resource.TestCheckResourceAttrSet("lxd_image.img", "architecture"),
+					resource.TestCheckResourceAttrSet("lxd_image.img", "created_at"),
+				),
+			},
+			{
+				// Update aliases, properties, and public flag in place.
+				Config: acctest.Provider() + testAccImage_unified(imagePath, alias+"-new", "2", true),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("lxd_image.img", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_image.img", "aliases.#", "1"),
+					resource.TestCheckTypeSetElemAttr("lxd_image.img", "aliases.*", alias+"-new"),
+					resource.TestCheckResourceAttr("lxd_image.img", "properties.version", "2"),
+					resource.TestCheckResourceAttr("lxd_image.img", "public", "true"),
+					resource.TestCheckResourceAttr("lxd_image.img", "fingerprint", testImageFingerprint(t, imagePath)),
+				),
+			},
+			{
+				// Modify the local image file, which re-uploads the image.
+				PreConfig: func() { writeTestImageTarball(t, imagePath, true, "v2") },
+				Config:    acctest.Provider() + testAccImage_unified(imagePath, alias+"-new", "2", true),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("lxd_image.img", plancheck.ResourceActionReplace),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckTypeSetElemAttr("lxd_image.img", "aliases.*", alias+"-new"),
+					resource.TestCheckResourceAttrWith("lxd_image.img", "fingerprint", func(value string) error {
+						expected := testImageFingerprint(t, imagePath)
+						if value != expected {
+							return fmt.Errorf("Expected fingerprint %q, got %q", expected, value)
+						}
+
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccImage_split(t *testing.T) {
+	dir := t.TempDir()
+	metaPath := filepath.Join(dir, "meta.tar.gz")
+	rootfsPath := filepath.Join(dir, "rootfs.tar.gz")
+	writeTestImageTarball(t, metaPath, false, "v1")
+	writeTestRootfsTarball(t, rootfsPath)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccImage_split(metaPath, rootfsPath),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_image.img", "metadata_path", metaPath),
+					resource.TestCheckResourceAttr("lxd_image.img", "rootfs_path", rootfsPath),
+					resource.TestCheckResourceAttr("lxd_image.img", "type", "container"),
+					resource.TestCheckResourceAttr("lxd_image.img", "fingerprint", testImageFingerprint(t, metaPath, rootfsPath)),
+					resource.TestCheckNoResourceAttr("lxd_image.img", "properties"),
+				),
+			},
+			{
+				// Ensure no changes happen.
+				Config: acctest.Provider() + testAccImage_split(metaPath, rootfsPath),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
+
+func testAccImage_unified(imagePath string, alias string, version string, public bool) string {
+	return fmt.Sprintf(`
+resource "lxd_image" "img" {
+  metadata_path = "%s"
+  aliases       = ["%s"]
+  public        = %t
+
+  properties = {
+    "version" = "%s"
+  }
+}
+	`, imagePath, alias, public, version)
+}
+
+func testAccImage_split(metaPath string, rootfsPath string) string {
+	return fmt.Sprintf(`
+resource "lxd_image" "img" {
+  metadata_path = "%s"
+  rootfs_path   = "%s"
+}
+	`, metaPath, rootfsPath)
+}
+
+// testImageFingerprint returns the expected fingerprint of an image
+// uploaded from the given files.
+func testImageFingerprint(t *testing.T, paths ...string) string {
+	fingerprint, err := sha256Files(paths...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return fingerprint
+}
+
+// sha256Files returns SHA-256 checksum of the concatenated file contents.
+func sha256Files(paths ...string) (string, error) {
+	hash := sha256.New()
+	for _, p := range paths {
+		content, err := os.ReadFile(p)
+		if err != nil {
+			return "", err
+		}
+
+		hash.Write(content)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// testImageArchitecture returns the LXD architecture name of the machine
+// running the tests.
+func testImageArchitecture() string {
+	switch runtime.GOARCH {
+	case "arm64":
+		return "aarch64"
+	default:
+		return "x86_64"
+	}
+}
+
+// writeTestImageTarball writes a minimal container image tarball. If
+// unified is true, the tarball contains an empty root filesystem. The
+// description is stored in the image metadata to produce distinct images.
+func writeTestImageTarball(t *testing.T, path string, unified bool, description string) {
+	metadata := fmt.Sprintf(`architecture: %s
+creation_date: %d
+properties:
+  os: test
+  description: %s
+`, testImageArchitecture(), time.Now().Unix(), description)
+
+	entries := map[string]string{
+		"metadata.yaml": metadata,
+	}
+
+	if unified {
+		entries["rootfs/"] = ""
+	}
+
+	writeTestTarball(t, path, entries)
+}
+
+// writeTestRootfsTarball writes a minimal root filesystem tarball.
+func writeTestRootfsTarball(t *testing.T, path string) {
+	writeTestTarball(t, path, map[string]string{
+		"etc/":         "",
+		"etc/hostname": "test\n",
+	})
+}
+
+// writeTestTarball writes a gzip compressed tarball with the given entries.
+// Entries with a trailing slash are written as directories.
+func writeTestTarball(t *testing.T, path string, entries map[string]string) {
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	for _, name := range utils.SortMapKeys(entries) {
+		content := entries[name]
+
+		hdr := &tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(content)),
+			ModTime: time.Now(),
+		}
+
+		if name[len(name)-1] == '/' {
+			hdr.Typeflag = tar.TypeDir
+			hdr.Mode = 0755
+		}
+
+		err := tw.WriteHeader(hdr)
+		if err == nil {
+			_, err = tw.Write([]byte(content))
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = tw.Close()
+	if err == nil {
+		err = gz.Close()
+	}
+
+	if err != nil {
+		t.Fatal(err)
+	}
+}
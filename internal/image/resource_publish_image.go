@@ -3,14 +3,22 @@ package image
 import (
 	"context"
 	"fmt"
+	"maps"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	lxd "github.com/canonical/lxd/client"
 	"github.com/canonical/lxd/shared/api"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
@@ -29,15 +37,24 @@ import (
 	"github.com/terraform-lxd/terraform-provider-lxd/internal/utils"
 )
 
+// compressionAlgorithms are the supported compression algorithms of
+// published images.
+var compressionAlgorithms = []string{"bzip2", "gzip", "lzma", "xz", "none"}
+
 // PublishImageModel resource data model that matches the schema.
 type PublishImageModel struct {
 	Instance       types.String `tfsdk:"instance"`
 	Aliases        types.Set    `tfsdk:"aliases"`
 	Properties     types.Map    `tfsdk:"properties"`
+	AutoProperties types.Bool   `tfsdk:"auto_properties"`
 	Public         types.Bool   `tfsdk:"public"`
+	AutoUpdate     types.Bool   `tfsdk:"auto_update"`
+	Profiles       types.List   `tfsdk:"profiles"`
+	ExpiresAt      types.String `tfsdk:"expires_at"`
 	Filename       types.String `tfsdk:"filename"`
 	CompressionAlg types.String `tfsdk:"compression_algorithm"`
 	Triggers       types.List   `tfsdk:"triggers"`
+	KeepLast       types.Int64  `tfsdk:"keep_last"`
 	Project        types.String `tfsdk:"project"`
 	Remote         types.String `tfsdk:"remote"`
 
@@ -45,6 +62,8 @@ type PublishImageModel struct {
 	Architecture types.String `tfsdk:"architecture"`
 	Fingerprint  types.String `tfsdk:"fingerprint"`
 	CreatedAt    types.Int64  `tfsdk:"created_at"`
+
+	PreviousFingerprints types.List `tfsdk:"previous_fingerprints"`
 }
 
 // PublishImageResource represent LXD publish image resource.
@@ -65,9 +84,19 @@ func (r PublishImageResource) Schema(_ context.Context, _ resource.SchemaRequest
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"instance": schema.StringAttribute{
-				Required: true,
+				Required:    true,
+				Description: "Name of the instance or instance snapshot (instance/snapshot) to publish",
 				PlanModifiers: []planmodifier.String{
-					stringplanmodifier.RequiresReplace(),
+					stringplanmodifier.RequiresReplaceIf(
+						func(ctx context.Context, req planmodifier.StringRequest, resp *stringplanmodifier.RequiresReplaceIfFuncResponse) {
+							resp.RequiresReplace, resp.Diagnostics = republishRequiresReplace(ctx, req.Plan)
+						},
+						"Replaces the resource unless previous publications are retained.",
+						"Replaces the resource unless previous publications are retained.",
+					),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^[^/]+(/[^/]+)?$`), "must be an instance name or instance/snapshot"),
 				},
 			},
 
@@ -91,12 +120,40 @@ func (r PublishImageResource) Schema(_ context.Context, _ resource.SchemaRequest
 				},
 			},
 
+			"auto_properties": schema.BoolAttribute{
+				Optional:    true,
+				Description: "Whether to derive os, release, version, and build_date properties when publishing the image",
+			},
+
 			"public": schema.BoolAttribute{
 				Optional: true,
 				Computed: true,
 				Default:  booldefault.StaticBool(false),
 			},
 
+			"auto_update": schema.BoolAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  booldefault.StaticBool(false),
+			},
+
+			"profiles": schema.ListAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "Profiles applied to instances created from the image",
+				Validators: []validator.List{
+					listvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+
+			"expires_at": schema.StringAttribute{
+				Optional:    true,
+				Description: "Image expiry date in RFC 3339 format",
+				Validators: []validator.String{
+					common.TimestampValidator{},
+				},
+			},
+
 			"filename": schema.StringAttribute{
 				Optional: true,
 				PlanModifiers: []planmodifier.String{
@@ -112,7 +169,7 @@ func (r PublishImageResource) Schema(_ context.Context, _ resource.SchemaRequest
 					stringplanmodifier.RequiresReplace(),
 				},
 				Validators: []validator.String{
-					stringvalidator.OneOf("bzip2", "gzip", "lzma", "xz", "none"),
+					stringvalidator.OneOf(compressionAlgorithms...),
 				},
 			},
 
@@ -120,7 +177,30 @@ func (r PublishImageResource) Schema(_ context.Context, _ resource.SchemaRequest
 				Optional:    true,
 				ElementType: types.StringType,
 				PlanModifiers: []planmodifier.List{
-					listplanmodifier.RequiresReplace(),
+					listplanmodifier.RequiresReplaceIf(
+						func(ctx context.Context, req planmodifier.ListRequest, resp *listplanmodifier.RequiresReplaceIfFuncResponse) {
+							adopted, diags := triggersAdopted(ctx, req.Private, req.StateValue)
+							resp.Diagnostics.Append(diags...)
+							if adopted {
+								return
+							}
+
+							var replace bool
+							replace, diags = republishRequiresReplace(ctx, req.Plan)
+							resp.Diagnostics.Append(diags...)
+							resp.RequiresReplace = replace
+						},
+						"Replaces the resource unless previous publications are retained.",
+						"Replaces the resource unless previous publications are retained.",
+					),
+				},
+			},
+
+			"keep_last": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of most recent publications to keep when triggers republish the image",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
 				},
 			},
 
@@ -162,6 +242,15 @@ func (r PublishImageResource) Schema(_ context.Context, _ resource.SchemaRequest
 					int64planmodifier.UseStateForUnknown(),
 				},
 			},
+
+			"previous_fingerprints": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Fingerprints of retained previous publications, from newest to oldest",
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.UseStateForUnknown(),
+				},
+			},
 		},
 	}
 }
@@ -181,6 +270,43 @@ func (r *PublishImageResource) Configure(_ context.Context, req resource.Configu
 	r.provider = provider
 }
 
+// ModifyPlan plans an in-place republication of the image when the source
+// instance or triggers change and previous publications are retained.
+func (r PublishImageResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || req.State.Raw.IsNull() {
+		return
+	}
+
+	var plan PublishImageModel
+	var state PublishImageModel
+
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.KeepLast.Equal(state.KeepLast) {
+		plan.PreviousFingerprints = types.ListUnknown(types.StringType)
+	}
+
+	adopted, diags := triggersAdopted(ctx, req.Private, state.Triggers)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	triggersChanged := !adopted && !plan.Triggers.Equal(state.Triggers)
+	if !plan.KeepLast.IsNull() && (!plan.Instance.Equal(state.Instance) || triggersChanged) {
+		plan.Architecture = types.StringUnknown()
+		plan.Fingerprint = types.StringUnknown()
+		plan.CreatedAt = types.Int64Unknown()
+		plan.PreviousFingerprints = types.ListUnknown(types.StringType)
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
 func (r PublishImageResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan PublishImageModel
 
@@ -198,19 +324,8 @@ func (r PublishImageResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
-	instanceName := plan.Instance.ValueString()
-	ct, _, err := server.GetInstanceState(instanceName)
-	if err != nil { // && errors.IsNotFoundError(err)
-		resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve state of instance %q", instanceName), err.Error())
-		return
-	}
-
-	if ct.StatusCode != api.Stopped {
-		resp.Diagnostics.AddError(fmt.Sprintf("Cannot publish image because instance %q is running", instanceName), "")
-		return
-	}
-
-	imageProps, diags := common.ToConfigMap(ctx, plan.Properties)
+	source := plan.Instance.ValueString()
+	settings, diags := toImageSettings(ctx, plan.Public, plan.AutoUpdate, types.MapNull(types.StringType), plan.Properties, plan.Profiles, plan.ExpiresAt)
 	resp.Diagnostics.Append(diags...)
 
 	aliases, diags := ToAliasList(ctx, plan.Aliases)
@@ -236,39 +351,9 @@ func (r PublishImageResource) Create(ctx context.Context, req resource.CreateReq
 		imageAliases = append(imageAliases, ia)
 	}
 
-	imageReq := api.ImagesPost{
-		Aliases:              imageAliases,
-		Filename:             plan.Filename.ValueString(),
-		CompressionAlgorithm: plan.CompressionAlg.ValueString(),
-		ImagePut: api.ImagePut{
-			Public:     plan.Public.ValueBool(),
-			Properties: imageProps,
-		},
-		Source: &api.ImagesPostSource{
-			Name: plan.Instance.ValueString(),
-			Type: "instance",
-		},
-	}
-
-	// Publish image.
-	op, err := server.CreateImage(imageReq, nil)
+	imageFingerprint, err := publishImage(ctx, server, plan, imageAliases, settings)
 	if err != nil {
-		resp.Diagnostics.AddError(fmt.Sprintf("Failed to publish instance %q image", instanceName), err.Error())
-		return
-	}
-
-	// Wait for create operation to finish.
-	err = op.WaitContext(ctx)
-	if err != nil {
-		resp.Diagnostics.AddError(fmt.Sprintf("Failed to publish instance %q image", instanceName), err.Error())
-		return
-	}
-
-	// Extract fingerprint from operation response.
-	opResp := op.Get()
-	imageFingerprint, ok := opResp.Metadata["fingerprint"].(string)
-	if !ok {
-		resp.Diagnostics.AddError("Failed to extract fingerprint from operation response", "")
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to publish image from %q", source), err.Error())
 		return
 	}
 
@@ -311,6 +396,16 @@ func (r PublishImageResource) Update(ctx context.Context, req resource.UpdateReq
 		return
 	}
 
+	// On failure, the planned state may contain unknown fingerprints.
+	// Retain the prior state instead, updated with the image that is
+	// currently published, so that no publication is lost from state.
+	current := state
+	defer func() {
+		if resp.Diagnostics.HasError() {
+			resp.Diagnostics.Append(resp.State.Set(ctx, &current)...)
+		}
+	}()
+
 	remote := plan.Remote.ValueString()
 	project := plan.Project.ValueString()
 	server, err := r.provider.InstanceServer(remote, project, "")
@@ -322,64 +417,105 @@ func (r PublishImageResource) Update(ctx context.Context, req resource.UpdateReq
 	// Extract image fingerprint from previous state.
 	imageFingerprint := state.Fingerprint.ValueString()
 
-	imageProps, diags := common.ToConfigMap(ctx, plan.Properties)
+	settings, diags := toImageSettings(ctx, plan.Public, plan.AutoUpdate, state.Properties, plan.Properties, plan.Profiles, plan.ExpiresAt)
 	resp.Diagnostics.Append(diags...)
 
-	// Get info about published image.
-	image, etag, err := server.GetImage(imageFingerprint)
-	if err != nil {
-		resp.Diagnostics.AddError("Failed to retrieve published image", err.Error())
-		return
-	}
-
-	oldAliases := make([]string, len(image.Aliases))
-	for i, alias := range image.Aliases {
-		oldAliases[i] = alias.Name
-	}
-
 	newAliases, diags := ToAliasList(ctx, plan.Aliases)
 	resp.Diagnostics.Append(diags...)
 
+	previous, diags := toFingerprintList(ctx, state.PreviousFingerprints)
+	resp.Diagnostics.Append(diags...)
+
 	if resp.Diagnostics.HasError() {
 		return
 	}
 
-	// Extract removed and added image aliases.
-	removed, added := utils.DiffSlices(oldAliases, newAliases)
+	// Triggers of an imported image are adopted from the configuration
+	// without republishing the image.
+	adopted, diags := triggersAdopted(ctx, req.Private, state.Triggers)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, importedKey, nil)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	// Delete removed aliases.
-	for _, alias := range removed {
-		err := server.DeleteImageAlias(alias)
+	// Republish the image when the source instance or triggers change.
+	// The aliases are moved to the new image and the old image is retained
+	// as a previous publication.
+	triggersChanged := !adopted && !plan.Triggers.Equal(state.Triggers)
+	if !plan.Instance.Equal(state.Instance) || triggersChanged {
+		image, _, err := server.GetImage(imageFingerprint)
 		if err != nil {
-			resp.Diagnostics.AddError(fmt.Sprintf("Failed to delete alias %q for published image", alias), err.Error())
+			resp.Diagnostics.AddError("Failed to retrieve published image", err.Error())
 			return
 		}
-	}
 
-	// Add new aliases.
-	for _, alias := range added {
-		req := api.ImageAliasesPost{}
-		req.Name = alias
-		req.Target = imageFingerprint
+		newFingerprint, err := publishImage(ctx, server, plan, nil, settings)
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to publish image from %q", plan.Instance.ValueString()), err.Error())
+			return
+		}
 
-		err := server.CreateImageAlias(req)
+		err = moveImageAliases(server, *image, newFingerprint)
 		if err != nil {
-			resp.Diagnostics.AddError(fmt.Sprintf("Failed to create alias %q for published image", alias), err.Error())
+			resp.Diagnostics.AddError("Failed to update published image", err.Error())
+
+			// Move the aliases back to the old image and remove the new
+			// image, so it is not orphaned.
+			newImage, _, err := server.GetImage(newFingerprint)
+			if err == nil {
+				err = moveImageAliases(server, *newImage, image.Fingerprint)
+			}
+
+			if err == nil {
+				err = deleteImage(server, newFingerprint)
+			}
+
+			if err != nil {
+				resp.Diagnostics.AddWarning(fmt.Sprintf("Failed to remove published image %q", newFingerprint), err.Error())
+			}
+
 			return
 		}
+
+		previous = append([]string{image.Fingerprint}, previous...)
+		imageFingerprint = newFingerprint
+
+		current.Fingerprint = types.StringValue(imageFingerprint)
+		current.PreviousFingerprints, diags = types.ListValueFrom(ctx, types.StringType, previous)
+		resp.Diagnostics.Append(diags...)
+	}
+
+	// Remove publications that exceed the number of retained ones.
+	keep := max(int(plan.KeepLast.ValueInt64()), 1) - 1
+	if len(previous) > keep {
+		for _, fingerprint := range previous[keep:] {
+			err := deleteImage(server, fingerprint)
+			if err != nil && !errors.IsNotFoundError(err) {
+				resp.Diagnostics.AddError(fmt.Sprintf("Failed to remove previous published image %q", fingerprint), err.Error())
+				return
+			}
+		}
+
+		previous = previous[:keep]
+	}
+
+	plan.Fingerprint = types.StringValue(imageFingerprint)
+	plan.PreviousFingerprints, diags = types.ListValueFrom(ctx, types.StringType, previous)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	imageReq := api.ImagePut{
-		Properties: imageProps,
-		Public:     plan.Public.ValueBool(),
-		ExpiresAt:  image.ExpiresAt,
-		AutoUpdate: image.AutoUpdate,
-		Profiles:   image.Profiles,
+	err = syncImageAliases(server, imageFingerprint, newAliases)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to update aliases of published image", err.Error())
+		return
 	}
 
-	err = server.UpdateImage(imageFingerprint, imageReq, etag)
+	err = updateImageSettings(server, imageFingerprint, settings)
 	if err != nil {
-		resp.Diagnostics.AddError("Failed to update publihsed image properties", err.Error())
+		resp.Diagnostics.AddError("Failed to update published image", err.Error())
 		return
 	}
 
@@ -405,6 +541,21 @@ func (r PublishImageResource) Delete(ctx context.Context, req resource.DeleteReq
 		return
 	}
 
+	// Remove retained previous publications.
+	previous, diags := toFingerprintList(ctx, state.PreviousFingerprints)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	for _, fingerprint := range previous {
+		err := deleteImage(server, fingerprint)
+		if err != nil && !errors.IsNotFoundError(err) {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to remove previous published image %q", fingerprint), err.Error())
+			return
+		}
+	}
+
 	imageFingerprint := state.Fingerprint.ValueString()
 	opDelete, err := server.DeleteImage(imageFingerprint)
 	if err != nil {
@@ -419,6 +570,127 @@ func (r PublishImageResource) Delete(ctx context.Context, req resource.DeleteReq
 	}
 }
 
+func (r PublishImageResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	meta := common.ImportMetadata{
+		ResourceName:   "publish_image",
+		RequiredFields: []string{"image"},
+		AllowedOptions: []string{
+			"instance", "compression_algorithm", "filename", "keep_last", "auto_properties",
+			"properties", "profiles", "expires_at",
+		},
+	}
+
+	fields, diag := meta.ParseImportID(req.ID)
+	if diag != nil {
+		resp.Diagnostics.Append(diag)
+		return
+	}
+
+	if fields["project"] == "" {
+		fields["project"] = provider_config.DefaultProject
+	}
+
+	imageName := fields["image"]
+
+	// LXD does not record the instance an image was published from.
+	if fields["instance"] == "" {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Failed to import published image %q", imageName),
+			"The instance of the image cannot be determined. Provide it using the \"instance\" option.",
+		)
+		return
+	}
+
+	// The compression algorithm is not recorded either, therefore the
+	// default one is assumed unless provided.
+	if fields["compression_algorithm"] == "" {
+		fields["compression_algorithm"] = "gzip"
+	}
+
+	if !utils.ValueInSlice(fields["compression_algorithm"], compressionAlgorithms) {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Failed to import published image %q", imageName),
+			fmt.Sprintf("Invalid value %q for option \"compression_algorithm\", must be one of: %s", fields["compression_algorithm"], strings.Join(compressionAlgorithms, ", ")),
+		)
+		return
+	}
+
+	var keepLast *int64
+	if fields["keep_last"] != "" {
+		n, err := strconv.ParseInt(fields["keep_last"], 10, 64)
+		if err != nil || n < 1 {
+			resp.Diagnostics.AddError(
+				fmt.Sprintf("Failed to import published image %q", imageName),
+				fmt.Sprintf("Invalid value %q for option \"keep_last\", must be a positive integer", fields["keep_last"]),
+			)
+			return
+		}
+
+		keepLast = &n
+	}
+
+	var autoProperties *bool
+	if fields["auto_properties"] != "" {
+		b, err := strconv.ParseBool(fields["auto_properties"])
+		if err != nil {
+			resp.Diagnostics.AddError(
+				fmt.Sprintf("Failed to import published image %q", imageName),
+				fmt.Sprintf("Invalid value %q for option \"auto_properties\": %v", fields["auto_properties"], err),
+			)
+			return
+		}
+
+		autoProperties = &b
+	}
+
+	server, err := r.provider.InstanceServer(fields["remote"], fields["project"], "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	image, err := importImage(server, imageName)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to import published image %q", imageName), err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(importImageSettings(ctx, &resp.State, image, fields)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if keepLast != nil {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("keep_last"), *keepLast)...)
+	}
+
+	if autoProperties != nil {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("auto_properties"), *autoProperties)...)
+	}
+
+	// Triggers cannot be recovered, so they are adopted from the
+	// configuration on the first apply after the import.
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, importedKey, []byte("true"))...)
+
+	delete(fields, "image")
+	delete(fields, "keep_last")
+	delete(fields, "auto_properties")
+	fields["fingerprint"] = image.Fingerprint
+
+	for k, v := range fields {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(k), v)...)
+	}
+
+	aliases := make([]string, 0, len(image.Aliases))
+	for _, a := range image.Aliases {
+		aliases = append(aliases, a.Name)
+	}
+
+	aliasSet, diags := ToAliasSetType(ctx, aliases)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("aliases"), aliasSet)...)
+}
+
 // SyncState fetches the server's current state for a published image and
 // updates the provided model. It then applies this updated model as the
 // new state in Terraform.
@@ -455,8 +727,40 @@ func (r PublishImageResource) SyncState(ctx context.Context, tfState *tfsdk.Stat
 	m.Fingerprint = types.StringValue(image.Fingerprint)
 	m.Architecture = types.StringValue(image.Architecture)
 	m.CreatedAt = types.Int64Value(image.CreatedAt.Unix())
+	properties, diags := ToImagePropertiesMapType(ctx, image.Properties, m.Properties)
+	respDiags.Append(diags...)
+
+	profiles, diags := ToImageProfilesListType(ctx, image.Profiles, m.Profiles)
+	respDiags.Append(diags...)
+
 	m.Public = types.BoolValue(image.Public)
+	m.AutoUpdate = types.BoolValue(image.AutoUpdate)
 	m.Aliases = aliasSet
+	m.Properties = properties
+	m.Profiles = profiles
+	m.ExpiresAt = ToImageExpiresAtType(image.ExpiresAt, m.ExpiresAt)
+
+	// Retain only previous publications that still exist.
+	previous, diags := toFingerprintList(ctx, m.PreviousFingerprints)
+	respDiags.Append(diags...)
+
+	existing := make([]string, 0, len(previous))
+	for _, fingerprint := range previous {
+		_, _, err := server.GetImage(fingerprint)
+		if err != nil {
+			if errors.IsNotFoundError(err) {
+				continue
+			}
+
+			respDiags.AddError(fmt.Sprintf("Failed to retrieve previous published image %q", fingerprint), err.Error())
+			return respDiags
+		}
+
+		existing = append(existing, fingerprint)
+	}
+
+	m.PreviousFingerprints, diags = types.ListValueFrom(ctx, types.StringType, existing)
+	respDiags.Append(diags...)
 
 	if respDiags.HasError() {
 		return respDiags
@@ -464,3 +768,147 @@ func (r PublishImageResource) SyncState(ctx context.Context, tfState *tfsdk.Stat
 
 	return tfState.Set(ctx, m)
 }
+
+// publishImage publishes an image from the instance or instance snapshot
+// referenced by the model and returns the fingerprint of the new image.
+// Instances must be stopped to be published, while snapshots can be
+// published at any time.
+func publishImage(ctx context.Context, server lxd.InstanceServer, m PublishImageModel, aliases []api.ImageAlias, settings imageSettings) (string, error) {
+	source := m.Instance.ValueString()
+	instanceName, snapshotName, isSnapshot := strings.Cut(source, "/")
+
+	var sourceType string
+	var config map[string]string
+	if isSnapshot {
+		snapshot, _, err := server.GetInstanceSnapshot(instanceName, snapshotName)
+		if err != nil {
+			return "", fmt.Errorf("Failed to retrieve snapshot %q of instance %q: %v", snapshotName, instanceName, err)
+		}
+
+		sourceType = "snapshot"
+		config = snapshot.ExpandedConfig
+	} else {
+		instance, _, err := server.GetInstance(instanceName)
+		if err != nil {
+			return "", fmt.Errorf("Failed to retrieve instance %q: %v", instanceName, err)
+		}
+
+		if instance.StatusCode != api.Stopped {
+			return "", fmt.Errorf("Instance %q is running. Stop the instance or publish one of its snapshots instead", instanceName)
+		}
+
+		sourceType = "instance"
+		config = instance.ExpandedConfig
+	}
+
+	// Configured properties take precedence over automatic ones.
+	properties := settings.newProps
+	if m.AutoProperties.ValueBool() {
+		properties = autoImageProperties(config, time.Now())
+		maps.Copy(properties, settings.newProps)
+	}
+
+	imageReq := api.ImagesPost{
+		Aliases:              aliases,
+		Filename:             m.Filename.ValueString(),
+		CompressionAlgorithm: m.CompressionAlg.ValueString(),
+		ImagePut: api.ImagePut{
+			Public:     settings.public,
+			AutoUpdate: settings.autoUpdate,
+			Properties: properties,
+			Profiles:   settings.profiles,
+		},
+		Source: &api.ImagesPostSource{
+			Name: source,
+			Type: sourceType,
+		},
+	}
+
+	// Publish image.
+	op, err := server.CreateImage(imageReq, nil)
+	if err != nil {
+		return "", err
+	}
+
+	// Wait for create operation to finish.
+	err = op.WaitContext(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	// Extract fingerprint from operation response.
+	opResp := op.Get()
+	imageFingerprint, ok := opResp.Metadata["fingerprint"].(string)
+	if !ok {
+		return "", fmt.Errorf("Failed to extract fingerprint from operation response")
+	}
+
+	// Expiry cannot be set when publishing the image.
+	if settings.expiresAt != nil {
+		err = updateImageSettings(server, imageFingerprint, settings)
+		if err != nil {
+			return "", fmt.Errorf("Failed to update published image %q: %v", imageFingerprint, err)
+		}
+	}
+
+	return imageFingerprint, nil
+}
+
+// autoImageProperties returns image properties derived from the image
+// configuration of the published instance and the publication time.
+func autoImageProperties(config map[string]string, buildDate time.Time) map[string]string {
+	properties := map[string]string{
+		"build_date": buildDate.UTC().Format(time.RFC3339),
+	}
+
+	for _, key := range []string{"os", "release", "version"} {
+		value := config["image."+key]
+		if value != "" {
+			properties[key] = value
+		}
+	}
+
+	return properties
+}
+
+// republishRequiresReplace reports whether a change of the published
+// source requires replacement of the resource. When previous publications
+// are retained, the image is republished in place instead.
+func republishRequiresReplace(ctx context.Context, plan tfsdk.Plan) (bool, diag.Diagnostics) {
+	var keepLast types.Int64
+	diags := plan.GetAttribute(ctx, path.Root("keep_last"), &keepLast)
+	return keepLast.IsNull(), diags
+}
+
+// importedKey is the private state key that marks a published image as
+// imported, until its triggers are adopted from the configuration.
+const importedKey = "imported"
+
+// privateState provides access to the private state of a resource.
+type privateState interface {
+	GetKey(ctx context.Context, key string) ([]byte, diag.Diagnostics)
+}
+
+// triggersAdopted reports whether the configured triggers are adopted
+// without republishing the image. This is the case for an imported image
+// that has no triggers recorded yet.
+func triggersAdopted(ctx context.Context, private privateState, stateTriggers types.List) (bool, diag.Diagnostics) {
+	if !stateTriggers.IsNull() {
+		return false, nil
+	}
+
+	imported, diags := private.GetKey(ctx, importedKey)
+	return len(imported) > 0, diags
+}
+
+// toFingerprintList converts a list of fingerprints into a slice of
+// strings.
+func toFingerprintList(ctx context.Context, list types.List) ([]string, diag.Diagnostics) {
+	if list.IsNull() || list.IsUnknown() {
+		return []string{}, nil
+	}
+
+	fingerprints := make([]string, 0, len(list.Elements()))
+	diags := list.ElementsAs(ctx, &fingerprints, false)
+	return fingerprints, diags
+}
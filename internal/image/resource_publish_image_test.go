@@ -2,10 +2,14 @@ package image_test
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
 	"github.com/terraform-lxd/terraform-provider-lxd/internal/acctest"
 )
 
@@ -91,6 +95,53 @@ func TestAccPublishImage_properties(t *testing.T) {
 	})
 }
 
+func TestAccPublishImage_updateSettings(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+	expiresAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second).Format(time.RFC3339)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccPublishImage_settings(instanceName, "1", false, ""),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_publish_image.pimg", "properties.%", "1"),
+					resource.TestCheckResourceAttr("lxd_publish_image.pimg", "properties.version", "1"),
+					resource.TestCheckResourceAttr("lxd_publish_image.pimg", "public", "false"),
+					resource.TestCheckNoResourceAttr("lxd_publish_image.pimg", "expires_at"),
+				),
+			},
+			{
+				// Update image settings in place.
+				Config: acctest.Provider() + testAccPublishImage_settings(instanceName, "2", true, expiresAt),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("lxd_publish_image.pimg", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_publish_image.pimg", "properties.%", "1"),
+					resource.TestCheckResourceAttr("lxd_publish_image.pimg", "properties.version", "2"),
+					resource.TestCheckResourceAttr("lxd_publish_image.pimg", "public", "true"),
+					resource.TestCheckResourceAttr("lxd_publish_image.pimg", "profiles.#", "1"),
+					resource.TestCheckResourceAttr("lxd_publish_image.pimg", "profiles.0", "default"),
+					resource.TestCheckResourceAttr("lxd_publish_image.pimg", "expires_at", expiresAt),
+				),
+			},
+			{
+				// Ensure no changes happen.
+				Config: acctest.Provider() + testAccPublishImage_settings(instanceName, "2", true, expiresAt),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectEmptyPlan(),
+					},
+				},
+			},
+		},
+	})
+}
+
 func TestAccPublishImage_project(t *testing.T) {
 	projectName := acctest.GenerateName(2, "")
 	instanceName := acctest.GenerateName(2, "-")
@@ -116,6 +167,259 @@ func TestAccPublishImage_project(t *testing.T) {
 	})
 }
 
+func TestAccPublishImage_importBasic(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+	resourceName := "lxd_publish_image.pimg"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccPublishImage_basic(instanceName),
+			},
+			{
+				// Import by alias.
+				ResourceName:                         resourceName,
+				ImportStateId:                        fmt.Sprintf("test_basic,instance=%s", instanceName),
+				ImportState:                          true,
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "fingerprint",
+			},
+			{
+				// Import by fingerprint.
+				ResourceName: resourceName,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					fingerprint := s.RootModule().Resources[resourceName].Primary.Attributes["fingerprint"]
+					return fmt.Sprintf("default/%s,instance=%s", fingerprint, instanceName), nil
+				},
+				ImportState:                          true,
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "fingerprint",
+			},
+			{
+				ResourceName:  resourceName,
+				ImportStateId: "test_basic",
+				ImportState:   true,
+				ExpectError:   regexp.MustCompile(`The instance of the image cannot be determined`),
+			},
+		},
+	})
+}
+
+func TestAccPublishImage_importCompression(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+	resourceName := "lxd_publish_image.pimg"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccPublishImage_compression(instanceName, "xz"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "compression_algorithm", "xz"),
+				),
+			},
+			{
+				ResourceName:                         resourceName,
+				ImportStateId:                        fmt.Sprintf("test_compression,instance=%s,compression_algorithm=xz", instanceName),
+				ImportState:                          true,
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "fingerprint",
+			},
+			{
+				ResourceName:  resourceName,
+				ImportStateId: fmt.Sprintf("test_compression,instance=%s,compression_algorithm=zip", instanceName),
+				ImportState:   true,
+				ExpectError:   regexp.MustCompile(`Invalid value "zip" for option "compression_algorithm"`),
+			},
+		},
+	})
+}
+
+func TestAccPublishImage_importSettings(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+	expiresAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second).Format(time.RFC3339)
+	resourceName := "lxd_publish_image.pimg"
+
+	importID := fmt.Sprintf("test_import,instance=%s,keep_last=2,auto_properties=true,properties=version,profiles=default,expires_at=%s", instanceName, expiresAt)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccPublishImage_importSettings(instanceName, expiresAt),
+			},
+			{
+				// Triggers cannot be recovered.
+				ResourceName:                         resourceName,
+				ImportStateId:                        importID,
+				ImportState:                          true,
+				ImportStateVerify:                    true,
+				ImportStateVerifyIdentifierAttribute: "fingerprint",
+				ImportStateVerifyIgnore:              []string{"triggers"},
+			},
+			{
+				ResourceName:       resourceName,
+				ImportStateId:      importID,
+				ImportState:        true,
+				ImportStatePersist: true,
+			},
+			{
+				// Triggers are recorded without republishing the image.
+				Config: acctest.Provider() + testAccPublishImage_importSettings(instanceName, expiresAt),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "triggers.#", "1"),
+					resource.TestCheckResourceAttr(resourceName, "triggers.0", "v1"),
+					resource.TestCheckResourceAttr(resourceName, "previous_fingerprints.#", "0"),
+				),
+			},
+			{
+				Config:   acctest.Provider() + testAccPublishImage_importSettings(instanceName, expiresAt),
+				PlanOnly: true,
+			},
+			{
+				ResourceName:  resourceName,
+				ImportStateId: fmt.Sprintf("test_import,instance=%s,keep_last=0", instanceName),
+				ImportState:   true,
+				ExpectError:   regexp.MustCompile(`Invalid value "0" for option "keep_last"`),
+			},
+		},
+	})
+}
+
+func TestAccPublishImage_snapshot(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+	alias := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccPublishImage_snapshot(instanceName, alias),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_publish_image.pimg", "instance", instanceName+"/snap0"),
+					resource.TestCheckResourceAttr("lxd_publish_image.pimg", "auto_properties", "true"),
+					resource.TestCheckResourceAttr("lxd_publish_image.pimg", "properties.%", "1"),
+					resource.TestCheckResourceAttr("lxd_publish_image.pimg", "properties.version", "custom"),
+					resource.TestCheckResourceAttrSet("lxd_publish_image.pimg", "fingerprint"),
+				),
+			},
+			{
+				// Automatic properties are not tracked, so the plan is empty.
+				Config: acctest.Provider() + testAccPublishImage_snapshot(instanceName, alias) + testAccPublishImage_imagesDataSource(alias),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("lxd_publish_image.pimg", plancheck.ResourceActionNoop),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.lxd_images.published", "images.#", "1"),
+					resource.TestCheckResourceAttrPair("data.lxd_images.published", "images.0.fingerprint", "lxd_publish_image.pimg", "fingerprint"),
+					resource.TestCheckResourceAttrSet("data.lxd_images.published", "images.0.properties.os"),
+					resource.TestCheckResourceAttrSet("data.lxd_images.published", "images.0.properties.build_date"),
+					resource.TestCheckResourceAttr("data.lxd_images.published", "images.0.properties.version", "custom"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPublishImage_keepLast(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+	alias := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccPublishImage_keepLast(instanceName, alias, 2, "1", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_publish_image.pimg", "keep_last", "2"),
+					resource.TestCheckResourceAttr("lxd_publish_image.pimg", "previous_fingerprints.#", "0"),
+				),
+			},
+			{
+				// Republish the image in place and retain the old one.
+				Config: acctest.Provider() + testAccPublishImage_keepLast(instanceName, alias, 2, "2", false),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("lxd_publish_image.pimg", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_publish_image.pimg", "previous_fingerprints.#", "1"),
+					resource.TestCheckResourceAttr("lxd_publish_image.pimg", "aliases.#", "1"),
+					resource.TestCheckResourceAttr("lxd_publish_image.pimg", "aliases.0", alias),
+				),
+			},
+			{
+				// The oldest publication is removed.
+				Config: acctest.Provider() + testAccPublishImage_keepLast(instanceName, alias, 2, "3", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_publish_image.pimg", "previous_fingerprints.#", "1"),
+				),
+			},
+			{
+				// Lowering the number of retained publications removes
+				// previous ones.
+				Config: acctest.Provider() + testAccPublishImage_keepLast(instanceName, alias, 1, "3", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_publish_image.pimg", "previous_fingerprints.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccPublishImage_keepLastFailure(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+	alias := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccPublishImage_keepLast(instanceName, alias, 2, "1", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("lxd_publish_image.pimg", "fingerprint"),
+					resource.TestCheckResourceAttr("lxd_publish_image.pimg", "previous_fingerprints.#", "0"),
+				),
+			},
+			{
+				// Republishing a running instance fails.
+				Config:      acctest.Provider() + testAccPublishImage_keepLast(instanceName, alias, 2, "2", true),
+				ExpectError: regexp.MustCompile(`Instance .* is running`),
+			},
+			{
+				// The published image is retained in state, so the image
+				// is republished once the instance is stopped.
+				Config: acctest.Provider() + testAccPublishImage_keepLast(instanceName, alias, 2, "2", false),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("lxd_publish_image.pimg", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_publish_image.pimg", "previous_fingerprints.#", "1"),
+					resource.TestCheckResourceAttr("lxd_publish_image.pimg", "aliases.0", alias),
+				),
+			},
+		},
+	})
+}
+
 func testAccPublishImage_basic(name string) string {
 	return fmt.Sprintf(`
 resource "lxd_instance" "instance1" {
@@ -131,6 +435,22 @@ resource "lxd_publish_image" "pimg" {
 	`, name, acctest.TestImage)
 }
 
+func testAccPublishImage_compression(name string, algorithm string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name    = "%s"
+  image   = "%s"
+  running = false
+}
+
+resource "lxd_publish_image" "pimg" {
+  instance              = lxd_instance.instance1.name
+  aliases               = ["test_compression"]
+  compression_algorithm = "%s"
+}
+	`, name, acctest.TestImage, algorithm)
+}
+
 func testAccPublishImage_aliases(name string, aliases ...string) string {
 	return fmt.Sprintf(`
 resource "lxd_instance" "instance1" {
@@ -163,6 +483,108 @@ resource "lxd_publish_image" "pimg" {
 	`, name, acctest.TestImage, strings.Join(formatProperties(properties), "\n"))
 }
 
+func testAccPublishImage_settings(name string, version string, public bool, expiresAt string) string {
+	settings := ""
+	if expiresAt != "" {
+		settings = fmt.Sprintf(`
+  profiles   = ["default"]
+  expires_at = %q`, expiresAt)
+	}
+
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name    = "%s"
+  image   = "%s"
+  running = false
+}
+
+resource "lxd_publish_image" "pimg" {
+  instance = lxd_instance.instance1.name
+  public   = %t
+  %s
+
+  properties = {
+    version = %q
+  }
+}
+	`, name, acctest.TestImage, public, settings, version)
+}
+
+func testAccPublishImage_importSettings(name string, expiresAt string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name    = "%s"
+  image   = "%s"
+  running = false
+}
+
+resource "lxd_publish_image" "pimg" {
+  instance        = lxd_instance.instance1.name
+  aliases         = ["test_import"]
+  auto_properties = true
+  profiles        = ["default"]
+  expires_at      = %q
+  keep_last       = 2
+  triggers        = ["v1"]
+
+  properties = {
+    version = "custom"
+  }
+}
+	`, name, acctest.TestImage, expiresAt)
+}
+
+func testAccPublishImage_snapshot(name string, alias string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+}
+
+resource "lxd_snapshot" "snap0" {
+  name     = "snap0"
+  instance = lxd_instance.instance1.name
+}
+
+resource "lxd_publish_image" "pimg" {
+  instance        = "${lxd_instance.instance1.name}/${lxd_snapshot.snap0.name}"
+  aliases         = ["%s"]
+  auto_properties = true
+
+  properties = {
+    version = "custom"
+  }
+}
+	`, name, acctest.TestImage, alias)
+}
+
+func testAccPublishImage_imagesDataSource(alias string) string {
+	return fmt.Sprintf(`
+data "lxd_images" "published" {
+  alias_prefix = "%s"
+
+  depends_on = [lxd_publish_image.pimg]
+}
+	`, alias)
+}
+
+func testAccPublishImage_keepLast(name string, alias string, keepLast int, trigger string, running bool) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name    = "%s"
+  image   = "%s"
+  running = %t
+}
+
+resource "lxd_publish_image" "pimg" {
+  instance  = lxd_instance.instance1.name
+  aliases   = ["%s"]
+  keep_last = %d
+  triggers  = ["%s"]
+}
+	`, name, acctest.TestImage, running, alias, keepLast, trigger)
+}
+
 func testAccPublishImage_project(project string, instance string) string {
 	return fmt.Sprintf(`
 resource "lxd_project" "project1" {
@@ -0,0 +1,118 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/common"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
+	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
+)
+
+type InstanceConsoleLogDataSourceModel struct {
+	Instance types.String `tfsdk:"instance"`
+	Lines    types.Int64  `tfsdk:"lines"`
+	Project  types.String `tfsdk:"project"`
+	Remote   types.String `tfsdk:"remote"`
+
+	// Computed
+	Content types.String `tfsdk:"content"`
+}
+
+type InstanceConsoleLogDataSource struct {
+	provider *provider_config.LxdProviderConfig
+}
+
+func NewInstanceConsoleLogDataSource() datasource.DataSource {
+	return &InstanceConsoleLogDataSource{}
+}
+
+func (d *InstanceConsoleLogDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = fmt.Sprintf("%s_instance_console_log", req.ProviderTypeName)
+}
+
+func (d *InstanceConsoleLogDataSource) Schema(_ context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"instance": schema.StringAttribute{
+				Required: true,
+			},
+
+			"lines": schema.Int64Attribute{
+				Optional:    true,
+				Description: "Number of lines to return from the end of the console log",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+
+			"project": schema.StringAttribute{
+				Optional: true,
+			},
+
+			"remote": schema.StringAttribute{
+				Optional: true,
+			},
+
+			// Computed.
+
+			"content": schema.StringAttribute{
+				Computed: true,
+			},
+		},
+	}
+}
+
+func (d *InstanceConsoleLogDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	data := req.ProviderData
+	if data == nil {
+		return
+	}
+
+	provider, ok := data.(*provider_config.LxdProviderConfig)
+	if !ok {
+		resp.Diagnostics.Append(errors.NewProviderDataTypeError(req.ProviderData))
+		return
+	}
+
+	d.provider = provider
+}
+
+func (d *InstanceConsoleLogDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state InstanceConsoleLogDataSourceModel
+
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := state.Remote.ValueString()
+	project := state.Project.ValueString()
+	server, err := d.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	instanceName := state.Instance.ValueString()
+	consoleLog, err := common.InstanceConsoleLog(server, instanceName)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve console log of instance %q", instanceName), err.Error())
+		return
+	}
+
+	if !state.Lines.IsNull() {
+		consoleLog = common.TailLines(consoleLog, int(state.Lines.ValueInt64()))
+	}
+
+	state.Content = types.StringValue(consoleLog)
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,81 @@
+package instance_test
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/acctest"
+)
+
+func TestAccInstanceConsoleLog_DS_basic(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceConsoleLog_DS_basic(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.lxd_instance_console_log.log", "instance", instanceName),
+					resource.TestCheckResourceAttrSet("data.lxd_instance_console_log.log", "content"),
+					resource.TestCheckResourceAttr("data.lxd_instance_console_log.tail", "lines", "5"),
+					resource.TestCheckResourceAttrWith("data.lxd_instance_console_log.tail", "content", func(value string) error {
+						lines := strings.Count(value, "\n") + 1
+						if lines > 5 {
+							return fmt.Errorf("Expected at most 5 lines, got %d", lines)
+						}
+
+						return nil
+					}),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceConsoleLog_DS_notFound(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.Provider() + testAccInstanceConsoleLog_DS_notFound(),
+				ExpectError: regexp.MustCompile(`Failed to retrieve console log of instance "not-found"`),
+			},
+		},
+	})
+}
+
+func testAccInstanceConsoleLog_DS_basic(instanceName string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  wait_for {
+    type = "agent"
+  }
+}
+
+data "lxd_instance_console_log" "log" {
+  instance = lxd_instance.instance1.name
+}
+
+data "lxd_instance_console_log" "tail" {
+  instance = lxd_instance.instance1.name
+  lines    = 5
+}
+	`, instanceName, acctest.TestImage)
+}
+
+func testAccInstanceConsoleLog_DS_notFound() string {
+	return `
+data "lxd_instance_console_log" "log" {
+  instance = "not-found"
+}
+	`
+}
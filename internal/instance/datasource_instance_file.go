@@ -0,0 +1,194 @@
+package instance
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"path"
+	"unicode/utf8"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/common"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
+	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
+)
+
+type InstanceFileDataSourceModel struct {
+	Instance types.String `tfsdk:"instance"`
+	Path     types.String `tfsdk:"path"`
+	Project  types.String `tfsdk:"project"`
+	Remote   types.String `tfsdk:"remote"`
+
+	// Computed
+	Content       types.String `tfsdk:"content"`
+	ContentBase64 types.String `tfsdk:"content_base64"`
+	SHA256        types.String `tfsdk:"sha256"`
+	Type          types.String `tfsdk:"type"`
+	UserID        types.Int64  `tfsdk:"uid"`
+	GroupID       types.Int64  `tfsdk:"gid"`
+	Mode          types.String `tfsdk:"mode"`
+	Entries       types.List   `tfsdk:"entries"`
+}
+
+type InstanceFileDataSource struct {
+	provider *provider_config.LxdProviderConfig
+}
+
+func NewInstanceFileDataSource() datasource.DataSource {
+	return &InstanceFileDataSource{}
+}
+
+func (d *InstanceFileDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = fmt.Sprintf("%s_instance_file", req.ProviderTypeName)
+}
+
+func (d *InstanceFileDataSource) Schema(_ context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"instance": schema.StringAttribute{
+				Required: true,
+			},
+
+			"path": schema.StringAttribute{
+				Required: true,
+			},
+
+			"project": schema.StringAttribute{
+				Optional: true,
+			},
+
+			"remote": schema.StringAttribute{
+				Optional: true,
+			},
+
+			// Computed.
+
+			"content": schema.StringAttribute{
+				Computed:    true,
+				Description: "File content, if it is a valid UTF-8 text",
+			},
+
+			"content_base64": schema.StringAttribute{
+				Computed:    true,
+				Description: "Base64 encoded file content",
+			},
+
+			"sha256": schema.StringAttribute{
+				Computed: true,
+			},
+
+			"type": schema.StringAttribute{
+				Computed: true,
+			},
+
+			"uid": schema.Int64Attribute{
+				Computed: true,
+			},
+
+			"gid": schema.Int64Attribute{
+				Computed: true,
+			},
+
+			"mode": schema.StringAttribute{
+				Computed: true,
+			},
+
+			"entries": schema.ListAttribute{
+				Computed:    true,
+				ElementType: types.StringType,
+				Description: "Directory entries, if the path is a directory",
+			},
+		},
+	}
+}
+
+func (d *InstanceFileDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	data := req.ProviderData
+	if data == nil {
+		return
+	}
+
+	provider, ok := data.(*provider_config.LxdProviderConfig)
+	if !ok {
+		resp.Diagnostics.Append(errors.NewProviderDataTypeError(req.ProviderData))
+		return
+	}
+
+	d.provider = provider
+}
+
+func (d *InstanceFileDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state InstanceFileDataSourceModel
+
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := state.Remote.ValueString()
+	project := state.Project.ValueString()
+	server, err := d.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	instanceName := state.Instance.ValueString()
+	filePath := state.Path.ValueString()
+	if !path.IsAbs(filePath) {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Invalid file path %q", filePath),
+			"File path must be an absolute path within the instance.",
+		)
+		return
+	}
+
+	reader, file, err := server.GetInstanceFile(instanceName, filePath)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve file %q from instance %q", filePath, instanceName), err.Error())
+		return
+	}
+
+	var content []byte
+	if reader != nil {
+		content, err = io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to read file %q from instance %q", filePath, instanceName), err.Error())
+			return
+		}
+	}
+
+	state.Type = types.StringValue(file.Type)
+	state.UserID = types.Int64Value(file.UID)
+	state.GroupID = types.Int64Value(file.GID)
+	state.Mode = types.StringValue(fmt.Sprintf("%04o", file.Mode))
+
+	if file.Type == "directory" {
+		state.Content = types.StringNull()
+		state.ContentBase64 = types.StringNull()
+		state.SHA256 = types.StringNull()
+
+		state.Entries, diags = types.ListValueFrom(ctx, types.StringType, file.Entries)
+		resp.Diagnostics.Append(diags...)
+	} else {
+		state.SHA256 = types.StringValue(common.ContentSHA256(string(content)))
+		state.ContentBase64 = types.StringValue(base64.StdEncoding.EncodeToString(content))
+		state.Entries = types.ListNull(types.StringType)
+
+		// Expose content as plain text only if it is valid UTF-8 text.
+		if utf8.Valid(content) && !bytes.ContainsRune(content, 0) {
+			state.Content = types.StringValue(string(content))
+		} else {
+			state.Content = types.StringNull()
+		}
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
@@ -0,0 +1,312 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
+	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
+)
+
+type InstanceStateDataSourceModel struct {
+	Instance types.String `tfsdk:"instance"`
+	Project  types.String `tfsdk:"project"`
+	Remote   types.String `tfsdk:"remote"`
+
+	// Computed
+	Status    types.String `tfsdk:"status"`
+	Pid       types.Int64  `tfsdk:"pid"`
+	Processes types.Int64  `tfsdk:"processes"`
+	CPU       types.Object `tfsdk:"cpu"`
+	Memory    types.Object `tfsdk:"memory"`
+	Disks     types.Map    `tfsdk:"disks"`
+	Network   types.Map    `tfsdk:"network"`
+}
+
+// InstanceStateCPUModel represents CPU usage of an instance.
+type InstanceStateCPUModel struct {
+	Usage types.Int64 `tfsdk:"usage"`
+}
+
+// InstanceStateMemoryModel represents memory usage of an instance.
+type InstanceStateMemoryModel struct {
+	Usage         types.Int64 `tfsdk:"usage"`
+	UsagePeak     types.Int64 `tfsdk:"usage_peak"`
+	Total         types.Int64 `tfsdk:"total"`
+	SwapUsage     types.Int64 `tfsdk:"swap_usage"`
+	SwapUsagePeak types.Int64 `tfsdk:"swap_usage_peak"`
+}
+
+// InstanceStateDiskModel represents usage of an instance disk.
+type InstanceStateDiskModel struct {
+	Usage types.Int64 `tfsdk:"usage"`
+	Total types.Int64 `tfsdk:"total"`
+}
+
+// InstanceStateNetworkModel represents state and counters of an
+// instance network interface.
+type InstanceStateNetworkModel struct {
+	HostName               types.String `tfsdk:"host_name"`
+	Hwaddr                 types.String `tfsdk:"hwaddr"`
+	MTU                    types.Int64  `tfsdk:"mtu"`
+	State                  types.String `tfsdk:"state"`
+	Type                   types.String `tfsdk:"type"`
+	BytesReceived          types.Int64  `tfsdk:"bytes_received"`
+	BytesSent              types.Int64  `tfsdk:"bytes_sent"`
+	PacketsReceived        types.Int64  `tfsdk:"packets_received"`
+	PacketsSent            types.Int64  `tfsdk:"packets_sent"`
+	ErrorsReceived         types.Int64  `tfsdk:"errors_received"`
+	ErrorsSent             types.Int64  `tfsdk:"errors_sent"`
+	PacketsDroppedInbound  types.Int64  `tfsdk:"packets_dropped_inbound"`
+	PacketsDroppedOutbound types.Int64  `tfsdk:"packets_dropped_outbound"`
+}
+
+var instanceStateCPUType = map[string]attr.Type{
+	"usage": types.Int64Type,
+}
+
+var instanceStateMemoryType = map[string]attr.Type{
+	"usage":           types.Int64Type,
+	"usage_peak":      types.Int64Type,
+	"total":           types.Int64Type,
+	"swap_usage":      types.Int64Type,
+	"swap_usage_peak": types.Int64Type,
+}
+
+var instanceStateDiskType = map[string]attr.Type{
+	"usage": types.Int64Type,
+	"total": types.Int64Type,
+}
+
+var instanceStateNetworkType = map[string]attr.Type{
+	"host_name":                types.StringType,
+	"hwaddr":                   types.StringType,
+	"mtu":                      types.Int64Type,
+	"state":                    types.StringType,
+	"type":                     types.StringType,
+	"bytes_received":           types.Int64Type,
+	"bytes_sent":               types.Int64Type,
+	"packets_received":         types.Int64Type,
+	"packets_sent":             types.Int64Type,
+	"errors_received":          types.Int64Type,
+	"errors_sent":              types.Int64Type,
+	"packets_dropped_inbound":  types.Int64Type,
+	"packets_dropped_outbound": types.Int64Type,
+}
+
+type InstanceStateDataSource struct {
+	provider *provider_config.LxdProviderConfig
+}
+
+func NewInstanceStateDataSource() datasource.DataSource {
+	return &InstanceStateDataSource{}
+}
+
+func (d *InstanceStateDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = fmt.Sprintf("%s_instance_state", req.ProviderTypeName)
+}
+
+func (d *InstanceStateDataSource) Schema(_ context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	int64Attrs := func(names ...string) map[string]schema.Attribute {
+		attrs := make(map[string]schema.Attribute, len(names))
+		for _, name := range names {
+			attrs[name] = schema.Int64Attribute{Computed: true}
+		}
+
+		return attrs
+	}
+
+	networkAttrs := int64Attrs(
+		"mtu",
+		"bytes_received",
+		"bytes_sent",
+		"packets_received",
+		"packets_sent",
+		"errors_received",
+		"errors_sent",
+		"packets_dropped_inbound",
+		"packets_dropped_outbound",
+	)
+
+	networkAttrs["host_name"] = schema.StringAttribute{Computed: true}
+	networkAttrs["hwaddr"] = schema.StringAttribute{Computed: true}
+	networkAttrs["state"] = schema.StringAttribute{Computed: true}
+	networkAttrs["type"] = schema.StringAttribute{Computed: true}
+
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"instance": schema.StringAttribute{
+				Required: true,
+			},
+
+			"project": schema.StringAttribute{
+				Optional: true,
+			},
+
+			"remote": schema.StringAttribute{
+				Optional: true,
+			},
+
+			// Computed.
+
+			"status": schema.StringAttribute{
+				Computed: true,
+			},
+
+			"pid": schema.Int64Attribute{
+				Computed:    true,
+				Description: "PID of the instance init process on the host",
+			},
+
+			"processes": schema.Int64Attribute{
+				Computed:    true,
+				Description: "Number of processes within the instance",
+			},
+
+			"cpu": schema.SingleNestedAttribute{
+				Computed:    true,
+				Description: "CPU usage of the instance in nanoseconds",
+				Attributes:  int64Attrs("usage"),
+			},
+
+			"memory": schema.SingleNestedAttribute{
+				Computed:    true,
+				Description: "Memory usage of the instance in bytes",
+				Attributes:  int64Attrs("usage", "usage_peak", "total", "swap_usage", "swap_usage_peak"),
+			},
+
+			"disks": schema.MapNestedAttribute{
+				Computed:    true,
+				Description: "Map of the instance disk usage in bytes",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: int64Attrs("usage", "total"),
+				},
+			},
+
+			"network": schema.MapNestedAttribute{
+				Computed:    true,
+				Description: "Map of the instance network interfaces and their counters",
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: networkAttrs,
+				},
+			},
+		},
+	}
+}
+
+func (d *InstanceStateDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	data := req.ProviderData
+	if data == nil {
+		return
+	}
+
+	provider, ok := data.(*provider_config.LxdProviderConfig)
+	if !ok {
+		resp.Diagnostics.Append(errors.NewProviderDataTypeError(req.ProviderData))
+		return
+	}
+
+	d.provider = provider
+}
+
+func (d *InstanceStateDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state InstanceStateDataSourceModel
+
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := state.Remote.ValueString()
+	project := state.Project.ValueString()
+	server, err := d.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	instanceName := state.Instance.ValueString()
+	instanceState, _, err := server.GetInstanceState(instanceName)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve state of instance %q", instanceName), err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(state.fromInstanceState(ctx, *instanceState)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// fromInstanceState populates the model with the runtime state of an instance.
+func (m *InstanceStateDataSourceModel) fromInstanceState(ctx context.Context, s api.InstanceState) diag.Diagnostics {
+	var diags diag.Diagnostics
+	var d diag.Diagnostics
+
+	m.Status = types.StringValue(s.Status)
+	m.Pid = types.Int64Value(s.Pid)
+	m.Processes = types.Int64Value(s.Processes)
+
+	cpu := InstanceStateCPUModel{
+		Usage: types.Int64Value(s.CPU.Usage),
+	}
+
+	m.CPU, d = types.ObjectValueFrom(ctx, instanceStateCPUType, cpu)
+	diags.Append(d...)
+
+	memory := InstanceStateMemoryModel{
+		Usage:         types.Int64Value(s.Memory.Usage),
+		UsagePeak:     types.Int64Value(s.Memory.UsagePeak),
+		Total:         types.Int64Value(s.Memory.Total),
+		SwapUsage:     types.Int64Value(s.Memory.SwapUsage),
+		SwapUsagePeak: types.Int64Value(s.Memory.SwapUsagePeak),
+	}
+
+	m.Memory, d = types.ObjectValueFrom(ctx, instanceStateMemoryType, memory)
+	diags.Append(d...)
+
+	disks := make(map[string]InstanceStateDiskModel, len(s.Disk))
+	for name, disk := range s.Disk {
+		disks[name] = InstanceStateDiskModel{
+			Usage: types.Int64Value(disk.Usage),
+			Total: types.Int64Value(disk.Total),
+		}
+	}
+
+	m.Disks, d = types.MapValueFrom(ctx, types.ObjectType{AttrTypes: instanceStateDiskType}, disks)
+	diags.Append(d...)
+
+	network := make(map[string]InstanceStateNetworkModel, len(s.Network))
+	for name, net := range s.Network {
+		network[name] = InstanceStateNetworkModel{
+			HostName:               types.StringValue(net.HostName),
+			Hwaddr:                 types.StringValue(net.Hwaddr),
+			MTU:                    types.Int64Value(int64(net.Mtu)),
+			State:                  types.StringValue(net.State),
+			Type:                   types.StringValue(net.Type),
+			BytesReceived:          types.Int64Value(net.Counters.BytesReceived),
+			BytesSent:              types.Int64Value(net.Counters.BytesSent),
+			PacketsReceived:        types.Int64Value(net.Counters.PacketsReceived),
+			PacketsSent:            types.Int64Value(net.Counters.PacketsSent),
+			ErrorsReceived:         types.Int64Value(net.Counters.ErrorsReceived),
+			ErrorsSent:             types.Int64Value(net.Counters.ErrorsSent),
+			PacketsDroppedInbound:  types.Int64Value(net.Counters.PacketsDroppedInbound),
+			PacketsDroppedOutbound: types.Int64Value(net.Counters.PacketsDroppedOutbound),
+		}
+	}
+
+	m.Network, d = types.MapValueFrom(ctx, types.ObjectType{AttrTypes: instanceStateNetworkType}, network)
+	diags.Append(d...)
+
+	return diags
+}
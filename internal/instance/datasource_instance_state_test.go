@@ -0,0 +1,110 @@
+package instance_test
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/acctest"
+)
+
+func TestAccInstanceState_DS_basic(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceState_DS_basic(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.lxd_instance_state.state", "instance", instanceName),
+					resource.TestCheckResourceAttr("data.lxd_instance_state.state", "status", "Running"),
+					resource.TestCheckResourceAttrSet("data.lxd_instance_state.state", "pid"),
+					resource.TestCheckResourceAttrSet("data.lxd_instance_state.state", "processes"),
+					resource.TestCheckResourceAttrSet("data.lxd_instance_state.state", "cpu.usage"),
+					resource.TestCheckResourceAttrSet("data.lxd_instance_state.state", "memory.usage"),
+					resource.TestCheckResourceAttrSet("data.lxd_instance_state.state", "memory.usage_peak"),
+					resource.TestCheckResourceAttrSet("data.lxd_instance_state.state", "memory.swap_usage"),
+					resource.TestCheckResourceAttrSet("data.lxd_instance_state.state", "disks.root.usage"),
+					resource.TestCheckResourceAttr("data.lxd_instance_state.state", "network.eth0.state", "up"),
+					resource.TestCheckResourceAttrSet("data.lxd_instance_state.state", "network.eth0.hwaddr"),
+					resource.TestCheckResourceAttrSet("data.lxd_instance_state.state", "network.eth0.bytes_received"),
+					resource.TestCheckResourceAttrSet("data.lxd_instance_state.state", "network.eth0.packets_sent"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceState_DS_stopped(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceState_DS_stopped(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.lxd_instance_state.state", "status", "Stopped"),
+					resource.TestCheckResourceAttr("data.lxd_instance_state.state", "pid", "0"),
+					resource.TestCheckResourceAttr("data.lxd_instance_state.state", "processes", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceState_DS_notFound(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.Provider() + testAccInstanceState_DS_notFound(),
+				ExpectError: regexp.MustCompile(`Failed to retrieve state of instance "not-found"`),
+			},
+		},
+	})
+}
+
+func testAccInstanceState_DS_basic(instanceName string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  wait_for {
+    type = "ipv4"
+  }
+}
+
+data "lxd_instance_state" "state" {
+  instance = lxd_instance.instance1.name
+}
+	`, instanceName, acctest.TestImage)
+}
+
+func testAccInstanceState_DS_stopped(instanceName string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name    = "%s"
+  image   = "%s"
+  running = false
+}
+
+data "lxd_instance_state" "state" {
+  instance = lxd_instance.instance1.name
+}
+	`, instanceName, acctest.TestImage)
+}
+
+func testAccInstanceState_DS_notFound() string {
+	return `
+data "lxd_instance_state" "state" {
+  instance = "not-found"
+}
+	`
+}
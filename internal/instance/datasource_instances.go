@@ -0,0 +1,332 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/canonical/lxd/shared/api"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
+	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/utils"
+)
+
+type InstancesDataSourceModel struct {
+	Project     types.String `tfsdk:"project"`
+	AllProjects types.Bool   `tfsdk:"all_projects"`
+	Remote      types.String `tfsdk:"remote"`
+	Status      types.String `tfsdk:"status"`
+	Type        types.String `tfsdk:"type"`
+	Profile     types.String `tfsdk:"profile"`
+	Location    types.String `tfsdk:"location"`
+	Config      types.Map    `tfsdk:"config"`
+
+	// Computed
+	Instances types.List `tfsdk:"instances"`
+}
+
+// InstancesItemModel represents a single instance returned by the
+// lxd_instances data source.
+type InstancesItemModel struct {
+	Name      types.String `tfsdk:"name"`
+	Project   types.String `tfsdk:"project"`
+	Type      types.String `tfsdk:"type"`
+	Status    types.String `tfsdk:"status"`
+	Location  types.String `tfsdk:"location"`
+	IPv4      types.String `tfsdk:"ipv4_address"`
+	IPv6      types.String `tfsdk:"ipv6_address"`
+	Addresses types.List   `tfsdk:"addresses"`
+}
+
+var instancesItemType = map[string]attr.Type{
+	"name":         types.StringType,
+	"project":      types.StringType,
+	"type":         types.StringType,
+	"status":       types.StringType,
+	"location":     types.StringType,
+	"ipv4_address": types.StringType,
+	"ipv6_address": types.StringType,
+	"addresses":    types.ListType{ElemType: types.StringType},
+}
+
+type InstancesDataSource struct {
+	provider *provider_config.LxdProviderConfig
+}
+
+func NewInstancesDataSource() datasource.DataSource {
+	return &InstancesDataSource{}
+}
+
+func (d *InstancesDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = fmt.Sprintf("%s_instances", req.ProviderTypeName)
+}
+
+func (d *InstancesDataSource) Schema(_ context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"project": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.ConflictsWith(path.MatchRoot("all_projects")),
+				},
+			},
+
+			"all_projects": schema.BoolAttribute{
+				Optional:    true,
+				Description: "List instances from all projects",
+			},
+
+			"remote": schema.StringAttribute{
+				Optional: true,
+			},
+
+			"status": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						api.Running.String(),
+						api.Stopped.String(),
+						api.Frozen.String(),
+						api.Error.String(),
+					),
+				},
+			},
+
+			"type": schema.StringAttribute{
+				Optional: true,
+				Validators: []validator.String{
+					stringvalidator.OneOf("container", "virtual-machine"),
+				},
+			},
+
+			"profile": schema.StringAttribute{
+				Optional:    true,
+				Description: "List only instances with the given profile applied",
+			},
+
+			"location": schema.StringAttribute{
+				Optional:    true,
+				Description: "List only instances located on the given cluster member",
+			},
+
+			"config": schema.MapAttribute{
+				Optional:    true,
+				ElementType: types.StringType,
+				Description: "List only instances with matching config entries",
+			},
+
+			// Computed.
+
+			"instances": schema.ListNestedAttribute{
+				Computed: true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
+							Computed: true,
+						},
+
+						"project": schema.StringAttribute{
+							Computed: true,
+						},
+
+						"type": schema.StringAttribute{
+							Computed: true,
+						},
+
+						"status": schema.StringAttribute{
+							Computed: true,
+						},
+
+						"location": schema.StringAttribute{
+							Computed: true,
+						},
+
+						"ipv4_address": schema.StringAttribute{
+							Computed: true,
+						},
+
+						"ipv6_address": schema.StringAttribute{
+							Computed: true,
+						},
+
+						"addresses": schema.ListAttribute{
+							Computed:    true,
+							ElementType: types.StringType,
+							Description: "Global IP addresses of the instance",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (d *InstancesDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	data := req.ProviderData
+	if data == nil {
+		return
+	}
+
+	provider, ok := data.(*provider_config.LxdProviderConfig)
+	if !ok {
+		resp.Diagnostics.Append(errors.NewProviderDataTypeError(req.ProviderData))
+		return
+	}
+
+	d.provider = provider
+}
+
+func (d *InstancesDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state InstancesDataSourceModel
+
+	diags := req.Config.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := state.Remote.ValueString()
+	project := state.Project.ValueString()
+	server, err := d.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	config := make(map[string]string, len(state.Config.Elements()))
+	diags = state.Config.ElementsAs(ctx, &config, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	filters := instanceFilters(state.Status.ValueString(), state.Location.ValueString(), config)
+	instanceType := api.InstanceType(state.Type.ValueString())
+
+	var instances []api.InstanceFull
+	if state.AllProjects.ValueBool() {
+		instances, err = server.GetInstancesFullAllProjectsWithFilter(instanceType, filters)
+	} else {
+		instances, err = server.GetInstancesFullWithFilter(instanceType, filters)
+	}
+
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to retrieve instances", err.Error())
+		return
+	}
+
+	// Sort instances by project and name to ensure consistent ordering.
+	slices.SortFunc(instances, func(a api.InstanceFull, b api.InstanceFull) int {
+		return strings.Compare(a.Project+"/"+a.Name, b.Project+"/"+b.Name)
+	})
+
+	profile := state.Profile.ValueString()
+	items := make([]InstancesItemModel, 0, len(instances))
+	for _, inst := range instances {
+		// Profiles are not supported by server-side filtering.
+		if profile != "" && !slices.Contains(inst.Profiles, profile) {
+			continue
+		}
+
+		ipv4, ipv6, addresses := instanceAddresses(inst)
+
+		addressList, diags := types.ListValueFrom(ctx, types.StringType, addresses)
+		resp.Diagnostics.Append(diags...)
+
+		items = append(items, InstancesItemModel{
+			Name:      types.StringValue(inst.Name),
+			Project:   types.StringValue(inst.Project),
+			Type:      types.StringValue(inst.Type),
+			Status:    types.StringValue(inst.Status),
+			Location:  types.StringValue(inst.Location),
+			IPv4:      ipv4,
+			IPv6:      ipv6,
+			Addresses: addressList,
+		})
+	}
+
+	state.Instances, diags = types.ListValueFrom(ctx, types.ObjectType{AttrTypes: instancesItemType}, items)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// instanceFilters returns server-side filters that match instances with
+// the given status, location, and config entries. Empty values are ignored.
+// Values are quoted, so they may contain spaces.
+func instanceFilters(status string, location string, config map[string]string) []string {
+	var filters []string
+
+	if status != "" {
+		filters = append(filters, "status eq "+strconv.Quote(status))
+	}
+
+	if location != "" {
+		filters = append(filters, "location eq "+strconv.Quote(location))
+	}
+
+	for _, k := range utils.SortMapKeys(config) {
+		filters = append(filters, fmt.Sprintf("config.%s eq %s", k, strconv.Quote(config[k])))
+	}
+
+	return filters
+}
+
+// instanceAddresses returns the IPv4 and IPv6 address of the instance,
+// determined the same way as for the lxd_instance data source, along
+// with all global addresses of the instance.
+func instanceAddresses(inst api.InstanceFull) (ipv4 types.String, ipv6 types.String, addresses []string) {
+	ipv4 = types.StringNull()
+	ipv6 = types.StringNull()
+	addresses = []string{}
+
+	if inst.State == nil {
+		return ipv4, ipv6, addresses
+	}
+
+	accIface := inst.ExpandedConfig["user.access_interface"]
+
+	for _, iface := range utils.SortMapKeys(inst.State.Network) {
+		if iface == "lo" {
+			continue
+		}
+
+		net := inst.State.Network[iface]
+		for _, ip := range net.Addresses {
+			if ip.Scope == "global" {
+				addresses = append(addresses, ip.Address)
+			}
+		}
+
+		if accIface != "" && accIface != iface {
+			continue
+		}
+
+		// Use addresses of the first interface that has any global address.
+		if ipv4.IsNull() && ipv6.IsNull() {
+			v4, v6 := findGlobalIPAddresses(net)
+			if v4 != "" {
+				ipv4 = types.StringValue(v4)
+			}
+
+			if v6 != "" {
+				ipv6 = types.StringValue(v6)
+			}
+		}
+	}
+
+	return ipv4, ipv6, addresses
+}
@@ -0,0 +1,50 @@
+package instance
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstanceFilters(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Status   string
+		Location string
+		Config   map[string]string
+		Result   []string
+	}{
+		{
+			Name:   "Empty",
+			Result: nil,
+		},
+		{
+			Name:     "Status and location",
+			Status:   "Running",
+			Location: "node1",
+			Result: []string{
+				`status eq "Running"`,
+				`location eq "node1"`,
+			},
+		},
+		{
+			Name: "Config with spaces",
+			Config: map[string]string{
+				"user.role":        "web server",
+				"image.os":         "Ubuntu",
+				"user.description": `say "hi"`,
+			},
+			Result: []string{
+				`config.image.os eq "Ubuntu"`,
+				`config.user.description eq "say \"hi\""`,
+				`config.user.role eq "web server"`,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assert.Equal(t, test.Result, instanceFilters(test.Status, test.Location, test.Config))
+		})
+	}
+}
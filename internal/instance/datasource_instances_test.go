@@ -0,0 +1,161 @@
+package instance_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/acctest"
+)
+
+func TestAccInstances_DS_basic(t *testing.T) {
+	projectName := acctest.GenerateName(2, "-")
+	instanceName1 := acctest.GenerateName(2, "-")
+	instanceName2 := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstances_DS_basic(projectName, instanceName1, instanceName2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.lxd_instances.all", "instances.#", "2"),
+					resource.TestCheckResourceAttr("data.lxd_instances.web", "instances.#", "1"),
+					resource.TestCheckResourceAttr("data.lxd_instances.web", "instances.0.name", instanceName1),
+					resource.TestCheckResourceAttr("data.lxd_instances.web", "instances.0.project", projectName),
+					resource.TestCheckResourceAttr("data.lxd_instances.web", "instances.0.type", "container"),
+					resource.TestCheckResourceAttr("data.lxd_instances.web", "instances.0.status", "Running"),
+					resource.TestCheckResourceAttrPair("data.lxd_instances.web", "instances.0.ipv4_address", "lxd_instance.instance1", "ipv4_address"),
+					resource.TestCheckResourceAttrPair("data.lxd_instances.web", "instances.0.location", "lxd_instance.instance1", "location"),
+					resource.TestCheckResourceAttr("data.lxd_instances.stopped", "instances.#", "1"),
+					resource.TestCheckResourceAttr("data.lxd_instances.stopped", "instances.0.name", instanceName2),
+					resource.TestCheckResourceAttr("data.lxd_instances.stopped", "instances.0.status", "Stopped"),
+					resource.TestCheckResourceAttr("data.lxd_instances.stopped", "instances.0.addresses.#", "0"),
+					resource.TestCheckResourceAttr("data.lxd_instances.vms", "instances.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstances_DS_profile(t *testing.T) {
+	projectName := acctest.GenerateName(2, "-")
+	profileName := acctest.GenerateName(2, "-")
+	instanceName1 := acctest.GenerateName(2, "-")
+	instanceName2 := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstances_DS_profile(projectName, profileName, instanceName1, instanceName2),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.lxd_instances.profile", "instances.#", "1"),
+					resource.TestCheckResourceAttr("data.lxd_instances.profile", "instances.0.name", instanceName2),
+				),
+			},
+		},
+	})
+}
+
+func testAccInstances_DS_project(projectName string) string {
+	return fmt.Sprintf(`
+resource "lxd_project" "project1" {
+  name = "%s"
+  config = {
+    "features.images"   = false
+    "features.profiles" = false
+  }
+}
+	`, projectName)
+}
+
+func testAccInstances_DS_basic(projectName string, instanceName1 string, instanceName2 string) string {
+	return testAccInstances_DS_project(projectName) + fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name    = "%[1]s"
+  image   = "%[3]s"
+  project = lxd_project.project1.name
+
+  config = {
+    "user.role" = "web"
+  }
+
+  wait_for {
+    type = "ipv4"
+  }
+}
+
+resource "lxd_instance" "instance2" {
+  name    = "%[2]s"
+  image   = "%[3]s"
+  project = lxd_project.project1.name
+  running = false
+
+  config = {
+    "user.role" = "db"
+  }
+}
+
+data "lxd_instances" "all" {
+  project = lxd_project.project1.name
+
+  depends_on = [lxd_instance.instance1, lxd_instance.instance2]
+}
+
+data "lxd_instances" "web" {
+  project = lxd_project.project1.name
+  config = {
+    "user.role" = "web"
+  }
+
+  depends_on = [lxd_instance.instance1, lxd_instance.instance2]
+}
+
+data "lxd_instances" "stopped" {
+  project = lxd_project.project1.name
+  status  = "Stopped"
+
+  depends_on = [lxd_instance.instance1, lxd_instance.instance2]
+}
+
+data "lxd_instances" "vms" {
+  project = lxd_project.project1.name
+  type    = "virtual-machine"
+
+  depends_on = [lxd_instance.instance1, lxd_instance.instance2]
+}
+	`, instanceName1, instanceName2, acctest.TestImage)
+}
+
+func testAccInstances_DS_profile(projectName string, profileName string, instanceName1 string, instanceName2 string) string {
+	return testAccInstances_DS_project(projectName) + fmt.Sprintf(`
+resource "lxd_profile" "profile1" {
+  name = "%[1]s"
+}
+
+resource "lxd_instance" "instance1" {
+  name    = "%[2]s"
+  image   = "%[4]s"
+  project = lxd_project.project1.name
+  running = false
+}
+
+resource "lxd_instance" "instance2" {
+  name     = "%[3]s"
+  image    = "%[4]s"
+  project  = lxd_project.project1.name
+  profiles = ["default", lxd_profile.profile1.name]
+  running  = false
+}
+
+data "lxd_instances" "profile" {
+  project = lxd_project.project1.name
+  profile = lxd_profile.profile1.name
+
+  depends_on = [lxd_instance.instance1, lxd_instance.instance2]
+}
+	`, profileName, instanceName1, instanceName2, acctest.TestImage)
+}
@@ -3,8 +3,10 @@ package instance
 import (
 	"context"
 	"fmt"
+	"net"
 	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,6 +14,7 @@ import (
 	"github.com/canonical/lxd/shared/api"
 	"github.com/canonical/lxd/shared/units"
 	"github.com/hashicorp/terraform-plugin-framework-timeouts/resource/timeouts"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
@@ -22,6 +25,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapdefault"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
@@ -29,6 +33,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/terraform-lxd/terraform-provider-lxd/internal/common"
 	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
@@ -37,22 +43,29 @@ import (
 )
 
 type InstanceModel struct {
-	Name           types.String `tfsdk:"name"`
-	Description    types.String `tfsdk:"description"`
-	Type           types.String `tfsdk:"type"`
-	Image          types.String `tfsdk:"image"`
-	Ephemeral      types.Bool   `tfsdk:"ephemeral"`
-	Running        types.Bool   `tfsdk:"running"`
-	AllowRestart   types.Bool   `tfsdk:"allow_restart"`
-	WaitForConfigs types.Set    `tfsdk:"wait_for"`
-	Profiles       types.List   `tfsdk:"profiles"`
-	Devices        types.Set    `tfsdk:"device"`
-	Files          types.Set    `tfsdk:"file"`
-	Execs          types.Map    `tfsdk:"execs"`
-	Config         types.Map    `tfsdk:"config"`
-	Project        types.String `tfsdk:"project"`
-	Remote         types.String `tfsdk:"remote"`
-	Target         types.String `tfsdk:"target"`
+	Name              types.String `tfsdk:"name"`
+	Description       types.String `tfsdk:"description"`
+	Type              types.String `tfsdk:"type"`
+	Image             types.String `tfsdk:"image"`
+	Architecture      types.String `tfsdk:"architecture"`
+	Ephemeral         types.Bool   `tfsdk:"ephemeral"`
+	Running           types.Bool   `tfsdk:"running"`
+	State             types.String `tfsdk:"state"`
+	StatefulStop      types.Bool   `tfsdk:"stateful_stop"`
+	StopTimeout       types.String `tfsdk:"stop_timeout"`
+	ForceStopOnDelete types.Bool   `tfsdk:"force_stop_on_delete"`
+	AllowRestart      types.Bool   `tfsdk:"allow_restart"`
+	WaitForConfigs    types.Set    `tfsdk:"wait_for"`
+	Profiles          types.List   `tfsdk:"profiles"`
+	Devices           types.Set    `tfsdk:"device"`
+	Files             types.Set    `tfsdk:"file"`
+	Execs             types.Map    `tfsdk:"execs"`
+	Config            types.Map    `tfsdk:"config"`
+	Project           types.String `tfsdk:"project"`
+	Remote            types.String `tfsdk:"remote"`
+	Target            types.String `tfsdk:"target"`
+
+	SnapshotBeforeUpdate types.Object `tfsdk:"snapshot_before_update"`
 
 	// Computed.
 	IPv4       types.String `tfsdk:"ipv4_address"`
@@ -76,9 +89,14 @@ func (m InstanceModel) IsVirtualMachine() bool {
 
 // WaitForModel represents a single wait_for block.
 type WaitForModel struct {
-	Type  types.String `tfsdk:"type"`
-	Delay types.String `tfsdk:"delay"`
-	Nic   types.String `tfsdk:"nic"`
+	Type     types.String `tfsdk:"type"`
+	Delay    types.String `tfsdk:"delay"`
+	Nic      types.String `tfsdk:"nic"`
+	Command  types.List   `tfsdk:"command"`
+	Path     types.String `tfsdk:"path"`
+	Port     types.Int64  `tfsdk:"port"`
+	Interval types.String `tfsdk:"interval"`
+	Retries  types.Int64  `tfsdk:"retries"`
 }
 
 func (m WaitForModel) IsAgent() bool {
@@ -101,6 +119,44 @@ func (m WaitForModel) IsReady() bool {
 	return m.Type.ValueString() == "ready"
 }
 
+func (m WaitForModel) IsCloudInit() bool {
+	return m.Type.ValueString() == "cloud-init"
+}
+
+func (m WaitForModel) IsExec() bool {
+	return m.Type.ValueString() == "exec"
+}
+
+func (m WaitForModel) IsFile() bool {
+	return m.Type.ValueString() == "file"
+}
+
+func (m WaitForModel) IsPort() bool {
+	return m.Type.ValueString() == "port"
+}
+
+// SnapshotBeforeUpdateModel represents the snapshot_before_update block.
+type SnapshotBeforeUpdateModel struct {
+	Enabled  types.Bool  `tfsdk:"enabled"`
+	Keep     types.Int64 `tfsdk:"keep"`
+	Stateful types.Bool  `tfsdk:"stateful"`
+}
+
+// preUpdateSnapshotPrefix is a name prefix of snapshots that are taken
+// by the provider before the instance is updated.
+const preUpdateSnapshotPrefix = "tf-pre-update-"
+
+// consoleLogTailLines is the number of instance console log lines
+// included in diagnostics when waiting for an instance times out.
+const consoleLogTailLines = 30
+
+// Supported values of the instance "state" attribute.
+const (
+	instanceStateRunning = "running"
+	instanceStateStopped = "stopped"
+	instanceStateFrozen  = "frozen"
+)
+
 // InstanceResource represent LXD instance resource.
 type InstanceResource struct {
 	provider *provider_config.LxdProviderConfig
@@ -147,6 +203,17 @@ func (r InstanceResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 				},
 			},
 
+			"architecture": schema.StringAttribute{
+				Optional:    true,
+				Description: "Architecture of the image used to create the instance",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					common.ArchitectureValidator{},
+				},
+			},
+
 			"ephemeral": schema.BoolAttribute{
 				Optional: true,
 				Computed: true,
@@ -162,6 +229,37 @@ func (r InstanceResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 				Default:  booldefault.StaticBool(true),
 			},
 
+			"state": schema.StringAttribute{
+				Description: "Desired state of the instance. Can be running, stopped, or frozen.",
+				Optional:    true,
+				Computed:    true,
+				Validators: []validator.String{
+					stringvalidator.OneOf(instanceStateRunning, instanceStateStopped, instanceStateFrozen),
+				},
+			},
+
+			"stateful_stop": schema.BoolAttribute{
+				Description: "Preserve the runtime state of the instance when it is stopped by the provider.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+
+			"stop_timeout": schema.StringAttribute{
+				Description: "Time to wait for the instance to stop gracefully before it is forcefully stopped.",
+				Optional:    true,
+				Validators: []validator.String{
+					durationValidator{},
+				},
+			},
+
+			"force_stop_on_delete": schema.BoolAttribute{
+				Description: "Allow the instance to be forcefully stopped when it is deleted.",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+			},
+
 			"allow_restart": schema.BoolAttribute{
 				Description: "Allow instance to be stopped and restarted if required by the provider for operations like migration or renaming.",
 				Optional:    true,
@@ -292,6 +390,40 @@ func (r InstanceResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 							Optional:    true,
 						},
 
+						"stdin": schema.StringAttribute{
+							Description: "Input passed to the command's standard input",
+							Optional:    true,
+						},
+
+						"timeout": schema.StringAttribute{
+							Description: "Time to wait for the command to complete",
+							Optional:    true,
+							Validators: []validator.String{
+								durationValidator{},
+							},
+						},
+
+						"retries": schema.Int64Attribute{
+							Description: "Number of times a failed command is retried",
+							Optional:    true,
+							Validators: []validator.Int64{
+								int64validator.AtLeast(0),
+							},
+						},
+
+						"retry_interval": schema.StringAttribute{
+							Description: "Time to wait between command retries",
+							Optional:    true,
+							Validators: []validator.String{
+								durationValidator{},
+							},
+						},
+
+						"stream_output": schema.BoolAttribute{
+							Description: "Whether to stream command's output to the provider log",
+							Optional:    true,
+						},
+
 						// Computed.
 
 						"exit_code": schema.Int64Attribute{
@@ -399,6 +531,35 @@ func (r InstanceResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 		},
 
 		Blocks: map[string]schema.Block{
+			"snapshot_before_update": schema.SingleNestedBlock{
+				Description: "Snapshot the instance before it is updated and restore the snapshot if the update fails.",
+				Attributes: map[string]schema.Attribute{
+					"enabled": schema.BoolAttribute{
+						Description: "Whether to snapshot the instance before it is updated",
+						Optional:    true,
+						Computed:    true,
+						Default:     booldefault.StaticBool(true),
+					},
+
+					"keep": schema.Int64Attribute{
+						Description: "Number of pre-update snapshots to retain",
+						Optional:    true,
+						Computed:    true,
+						Default:     int64default.StaticInt64(1),
+						Validators: []validator.Int64{
+							int64validator.AtLeast(1),
+						},
+					},
+
+					"stateful": schema.BoolAttribute{
+						Description: "Whether to include the runtime state of a running instance in the snapshot",
+						Optional:    true,
+						Computed:    true,
+						Default:     booldefault.StaticBool(false),
+					},
+				},
+			},
+
 			"wait_for": schema.SetNestedBlock{
 				Description: "Wait for instance condition to be met once the instance is started.",
 				NestedObject: schema.NestedBlockObject{
@@ -408,9 +569,13 @@ func (r InstanceResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 							Validators: []validator.String{
 								stringvalidator.OneOf(
 									"agent",
+									"cloud-init",
 									"delay",
+									"exec",
+									"file",
 									"ipv4",
 									"ipv6",
+									"port",
 									"ready",
 								),
 							},
@@ -418,11 +583,44 @@ func (r InstanceResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 
 						"delay": schema.StringAttribute{
 							Optional: true,
+							Validators: []validator.String{
+								durationValidator{},
+							},
 						},
 
 						"nic": schema.StringAttribute{
 							Optional: true,
 						},
+
+						"command": schema.ListAttribute{
+							Optional:    true,
+							ElementType: types.StringType,
+						},
+
+						"path": schema.StringAttribute{
+							Optional: true,
+						},
+
+						"port": schema.Int64Attribute{
+							Optional: true,
+							Validators: []validator.Int64{
+								int64validator.Between(1, 65535),
+							},
+						},
+
+						"interval": schema.StringAttribute{
+							Optional: true,
+							Validators: []validator.String{
+								durationValidator{},
+							},
+						},
+
+						"retries": schema.Int64Attribute{
+							Optional: true,
+							Validators: []validator.Int64{
+								int64validator.AtLeast(0),
+							},
+						},
 					},
 				},
 			},
@@ -465,6 +663,17 @@ func (r InstanceResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 				Description: "Upload file to instance",
 				NestedObject: schema.NestedBlockObject{
 					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{
+							Optional: true,
+							Validators: []validator.String{
+								stringvalidator.OneOf(
+									common.FileTypeFile,
+									common.FileTypeDirectory,
+									common.FileTypeSymlink,
+								),
+							},
+						},
+
 						"content": schema.StringAttribute{
 							Optional: true,
 						},
@@ -473,6 +682,10 @@ func (r InstanceResource) Schema(ctx context.Context, _ resource.SchemaRequest,
 							Optional: true,
 						},
 
+						"symlink_target": schema.StringAttribute{
+							Optional: true,
+						},
+
 						"target_path": schema.StringAttribute{
 							Required: true,
 						},
@@ -532,10 +745,102 @@ func (r *InstanceResource) ModifyPlan(ctx context.Context, req resource.ModifyPl
 		return
 	}
 
+	// Nothing to modify on destroy.
+	if req.Config.Raw.IsNull() {
+		return
+	}
+
 	// If profiles in config are null, set "default" profile in plan.
-	if !req.Config.Raw.IsNull() && config.Profiles.IsNull() {
+	if config.Profiles.IsNull() {
 		resp.Plan.SetAttribute(ctx, path.Root("profiles"), []string{"default"})
 	}
+
+	// Keep "running" and "state" attributes consistent, so that the plan
+	// reflects the actual state transition of the instance.
+	if !config.State.IsNull() {
+		running := types.BoolUnknown()
+		if !config.State.IsUnknown() {
+			running = types.BoolValue(config.State.ValueString() == instanceStateRunning)
+		}
+
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("running"), running)...)
+	} else {
+		var running types.Bool
+		resp.Diagnostics.Append(resp.Plan.GetAttribute(ctx, path.Root("running"), &running)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		state := types.StringUnknown()
+		if !running.IsUnknown() {
+			state = types.StringValue(instanceStateStopped)
+			if running.ValueBool() {
+				state = types.StringValue(instanceStateRunning)
+			}
+		}
+
+		resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("state"), state)...)
+	}
+
+	// Nothing else to check on create or if the provider is not configured.
+	if req.State.Raw.IsNull() || r.provider == nil || resp.Diagnostics.HasError() {
+		return
+	}
+
+	var plan InstanceModel
+	var state InstanceModel
+
+	resp.Diagnostics.Append(resp.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Only a running instance that is planned to remain running may
+	// require restart.
+	if state.State.ValueString() == instanceStateStopped || plan.State.ValueString() == instanceStateStopped {
+		return
+	}
+
+	// Changes cannot be determined until the plan is fully known.
+	if plan.Config.IsUnknown() || plan.Devices.IsUnknown() || plan.Remote.IsUnknown() || plan.Project.IsUnknown() {
+		return
+	}
+
+	// Avoid retrieving the configuration metadata if there is nothing
+	// to check.
+	if plan.Config.Equal(state.Config) && plan.Devices.Equal(state.Devices) {
+		return
+	}
+
+	server, err := r.provider.InstanceServer(plan.Remote.ValueString(), plan.Project.ValueString(), "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	restartKeys, diags := restartRequiredChanges(ctx, server, state, plan)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	if len(restartKeys) == 0 {
+		return
+	}
+
+	summary := fmt.Sprintf("Instance %q requires restart", state.Name.ValueString())
+	detail := fmt.Sprintf("Changes to the following keys cannot be applied to the running instance: %s.", strings.Join(restartKeys, ", "))
+
+	if plan.AllowRestart.ValueBool() {
+		resp.Diagnostics.AddWarning(summary, detail+" The instance will be restarted.")
+	} else {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("allow_restart"),
+			summary,
+			detail+` Either stop the instance manually or set the "allow_restart" attribute to "true".`,
+		)
+	}
 }
 
 func (r InstanceResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
@@ -562,6 +867,19 @@ func (r InstanceResource) ValidateConfig(ctx context.Context, req resource.Valid
 		running = config.Running.ValueBool()
 	}
 
+	// Attributes "running" and "state" are mutually exclusive.
+	if !config.Running.IsNull() && !config.State.IsNull() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("state"),
+			"Invalid Configuration",
+			`Attributes "running" and "state" cannot be set at the same time.`,
+		)
+	}
+
+	if !config.State.IsNull() && !config.State.IsUnknown() {
+		running = config.State.ValueString() != instanceStateStopped
+	}
+
 	// Ephemeral instance cannot be stopped.
 	if ephemeral && !running {
 		resp.Diagnostics.AddAttributeError(
@@ -571,6 +889,15 @@ func (r InstanceResource) ValidateConfig(ctx context.Context, req resource.Valid
 		)
 	}
 
+	// Ephemeral instance cannot be stopped statefully.
+	if ephemeral && config.StatefulStop.ValueBool() {
+		resp.Diagnostics.AddAttributeError(
+			path.Root("stateful_stop"),
+			fmt.Sprintf("Instance %q is ephemeral and cannot be stopped statefully", config.Name.ValueString()),
+			"Ephemeral instances are removed when stopped, therefore their runtime state cannot be preserved.",
+		)
+	}
+
 	// Ensure empty container cannot be started.
 	if running && (config.Image.IsNull() || config.Image.ValueString() == "") && config.Type.ValueString() == "container" {
 		resp.Diagnostics.AddAttributeError(
@@ -584,6 +911,19 @@ func (r InstanceResource) ValidateConfig(ctx context.Context, req resource.Valid
 		validateWaitFor(ctx, config, resp)
 	}
 
+	files, diags := common.ToFileMap(ctx, config.Files)
+	resp.Diagnostics.Append(diags...)
+	for _, targetPath := range utils.SortMapKeys(files) {
+		err := files[targetPath].Validate()
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(
+				path.Root("file"),
+				"Invalid Configuration",
+				fmt.Sprintf("Invalid file %q: %v", targetPath, err),
+			)
+		}
+	}
+
 	if config.IsVirtualMachine() {
 		if !config.Files.IsNull() {
 			validateWaitForAgent(ctx, config, resp, `Wait for "agent" is required when files are uploaded to a virtual machine.`)
@@ -605,11 +945,11 @@ func validateWaitFor(ctx context.Context, config InstanceModel, resp *resource.V
 	}
 
 	for _, waitFor := range waitForList {
-		// "nic" is only valid for ipv4/ipv6.
-		if !waitFor.IsIPv4() && !waitFor.IsIPv6() && !waitFor.Nic.IsNull() {
+		// "nic" is only valid for ipv4/ipv6/port.
+		if !waitFor.IsIPv4() && !waitFor.IsIPv6() && !waitFor.IsPort() && !waitFor.Nic.IsNull() {
 			resp.Diagnostics.AddError(
 				"Invalid Configuration",
-				`The "nic" can only be set when wait_for type is "ipv4" or "ipv6".`,
+				`The "nic" can only be set when wait_for type is "ipv4", "ipv6", or "port".`,
 			)
 		}
 
@@ -621,26 +961,74 @@ func validateWaitFor(ctx context.Context, config InstanceModel, resp *resource.V
 			)
 		}
 
-		// "delay" requires the delay attribute and must be parsable.
-		if waitFor.IsDelay() {
-			if waitFor.Delay.IsNull() {
+		// "delay" requires the delay attribute.
+		if waitFor.IsDelay() && waitFor.Delay.IsNull() {
+			resp.Diagnostics.AddError(
+				"Invalid Configuration",
+				`The "delay" attribute is required when wait_for type is "delay".`,
+			)
+		}
+
+		// "delay" attribute is only valid for the "delay" type.
+		if !waitFor.IsDelay() && !waitFor.Delay.IsNull() {
+			resp.Diagnostics.AddError(
+				"Invalid Configuration",
+				`The "delay" attribute can only be set when wait_for type is "delay".`,
+			)
+		}
+
+		// "exec" requires a non-empty command.
+		if waitFor.IsExec() && (waitFor.Command.IsNull() || (!waitFor.Command.IsUnknown() && len(waitFor.Command.Elements()) == 0)) {
+			resp.Diagnostics.AddError(
+				"Invalid Configuration",
+				`The "command" attribute is required when wait_for type is "exec".`,
+			)
+		}
+
+		// "command", "interval", and "retries" attributes are only valid for the "exec" type.
+		if !waitFor.IsExec() && (!waitFor.Command.IsNull() || !waitFor.Interval.IsNull() || !waitFor.Retries.IsNull()) {
+			resp.Diagnostics.AddError(
+				"Invalid Configuration",
+				`The "command", "interval", and "retries" attributes can only be set when wait_for type is "exec".`,
+			)
+		}
+
+		// "file" requires an absolute path.
+		if waitFor.IsFile() {
+			if waitFor.Path.IsNull() {
 				resp.Diagnostics.AddError(
 					"Invalid Configuration",
-					`The "delay" attribute is required when wait_for type is "delay".`,
+					`The "path" attribute is required when wait_for type is "file".`,
 				)
-			} else if _, err := time.ParseDuration(waitFor.Delay.ValueString()); err != nil {
+			} else if !waitFor.Path.IsUnknown() && !strings.HasPrefix(waitFor.Path.ValueString(), "/") {
 				resp.Diagnostics.AddError(
 					"Invalid Configuration",
-					fmt.Sprintf("Invalid wait_for delay duration %q: %v.", waitFor.Delay.ValueString(), err),
+					fmt.Sprintf("The wait_for path %q must be absolute.", waitFor.Path.ValueString()),
 				)
 			}
 		}
 
-		// "delay" attribute is only valid for the "delay" type.
-		if !waitFor.IsDelay() && !waitFor.Delay.IsNull() {
+		// "path" attribute is only valid for the "file" type.
+		if !waitFor.IsFile() && !waitFor.Path.IsNull() {
 			resp.Diagnostics.AddError(
 				"Invalid Configuration",
-				`The "delay" attribute can only be set when wait_for type is "delay".`,
+				`The "path" attribute can only be set when wait_for type is "file".`,
+			)
+		}
+
+		// "port" requires the port attribute.
+		if waitFor.IsPort() && waitFor.Port.IsNull() {
+			resp.Diagnostics.AddError(
+				"Invalid Configuration",
+				`The "port" attribute is required when wait_for type is "port".`,
+			)
+		}
+
+		// "port" attribute is only valid for the "port" type.
+		if !waitFor.IsPort() && !waitFor.Port.IsNull() {
+			resp.Diagnostics.AddError(
+				"Invalid Configuration",
+				`The "port" attribute can only be set when wait_for type is "port".`,
 			)
 		}
 	}
@@ -756,8 +1144,30 @@ func (r InstanceResource) Create(ctx context.Context, req resource.CreateRequest
 	// Gather info about source image.
 	conn, _ := imageServer.GetConnectionInfo()
 
+	architecture := plan.Architecture.ValueString()
+
 	if image == "" {
 		instance.Source.Type = api.SourceTypeNone
+	} else if architecture != "" {
+		// Ensure the target server can run the requested architecture.
+		err := common.ValidateServerArchitecture(server, target, architecture)
+		if err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("architecture"), fmt.Sprintf("Failed to create instance %q", instance.Name), err.Error())
+			return
+		}
+
+		// Resolve the image alias for the requested architecture.
+		image, err = common.ResolveImageArchitecture(imageServer, plan.Type.ValueString(), image, architecture)
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve image info for instance %q", instance.Name), err.Error())
+			return
+		}
+
+		imageInfo, _, err = imageServer.GetImage(image)
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve image info for instance %q", instance.Name), err.Error())
+			return
+		}
 	} else if conn.Protocol == "simplestreams" {
 		// Optimisation for simplestreams.
 		imageInfo = &api.Image{}
@@ -808,7 +1218,10 @@ func (r InstanceResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
-	if plan.Running.ValueBool() {
+	// Frozen instance has to be started first.
+	instanceStarted := plan.State.ValueString() != instanceStateStopped
+
+	if instanceStarted {
 		// Start the instance.
 		diag := startInstance(ctx, server, instance.Name)
 		if diag != nil {
@@ -834,8 +1247,10 @@ func (r InstanceResource) Create(ctx context.Context, req resource.CreateRequest
 			return
 		}
 
-		for _, f := range files {
-			err := common.InstanceFileUpload(server, instance.Name, f)
+		// Upload files in order of their target paths, which ensures
+		// directories are created before the files within them.
+		for _, targetPath := range utils.SortMapKeys(files) {
+			err := common.InstanceFileUpload(server, instance.Name, files[targetPath])
 			if err != nil {
 				resp.Diagnostics.AddError(fmt.Sprintf("Failed to upload file to instance %q", instance.Name), err.Error())
 				return
@@ -854,7 +1269,7 @@ func (r InstanceResource) Create(ctx context.Context, req resource.CreateRequest
 	for _, k := range utils.SortMapKeys(execs) {
 		e := execs[k]
 
-		if plan.Running.ValueBool() && e.IsTriggered(true) {
+		if instanceStarted && e.IsTriggered(true) {
 			diags := e.Execute(ctx, server, instance.Name)
 			if diags.HasError() {
 				resp.Diagnostics.Append(diags...)
@@ -870,6 +1285,15 @@ func (r InstanceResource) Create(ctx context.Context, req resource.CreateRequest
 		return
 	}
 
+	// Freeze the instance once it is fully provisioned.
+	if plan.State.ValueString() == instanceStateFrozen {
+		diag := freezeInstance(ctx, server, instance.Name)
+		if diag != nil {
+			resp.Diagnostics.Append(diag)
+			return
+		}
+	}
+
 	// Update Terraform state.
 	diags = r.SyncState(ctx, &resp.State, server, plan)
 	resp.Diagnostics.Append(diags...)
@@ -907,10 +1331,17 @@ func (r InstanceResource) Read(ctx context.Context, req resource.ReadRequest, re
 }
 
 // Update updates the instance in the following order:
+// - Snapshot the instance (if enabled)
+// - Unfreeze the instance (if frozen)
 // - Ensure instance state (stopped/running)
 // - Update configuration (config, limits, devices, profiles)
 // - Upload files
-// - Run exec commands.
+// - Run exec commands
+// - Freeze the instance (if requested)
+// - Remove old pre-update snapshots (if enabled).
+//
+// If the update fails and the pre-update snapshot was taken, the instance
+// is restored from that snapshot.
 func (r InstanceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
 	var plan InstanceModel
 	var state InstanceModel
@@ -989,64 +1420,126 @@ func (r InstanceResource) Update(ctx context.Context, req resource.UpdateRequest
 		requireInstanceMigration = !onExpectedLocation
 	}
 
-	// Ensure instance is stopped if required.
+	desiredState := plan.State.ValueString()
+	requireInstanceStop := false
+
+	// Determine whether the instance needs to be stopped, before anything
+	// is modified.
 	if !instanceStopped {
 		requireInstanceRestart := requireInstanceMigration || requireInstanceRename
 
-		// Currently memory for virtual machines cannot be live updated.
-		// Restart the virtual machine if provider is allowed to stop the instance
-		// and the limit was increased.
-		//
-		// XXX: Remove once https://github.com/canonical/lxd/issues/15010 is resolved.
-		if instance.Type == string(api.InstanceTypeVM) && plan.AllowRestart.ValueBool() {
-			oldMemory, err := units.ParseByteSizeString(instance.Config["limits.memory"])
-			if err != nil {
-				resp.Diagnostics.AddError("Failed to parse existing memory limit", err.Error())
-				return
-			}
-
-			newMemory, err := units.ParseByteSizeString(config["limits.memory"])
-			if err != nil {
-				resp.Diagnostics.AddError("Failed to parse new memory limit", err.Error())
-				return
-			}
+		// Restart the instance if any of the changes cannot be applied live.
+		restartKeys, diags := restartRequiredChanges(ctx, server, state, plan)
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
 
-			if newMemory > oldMemory {
-				requireInstanceRestart = true
-			}
+		if len(restartKeys) > 0 {
+			tflog.Info(ctx, "Instance changes require restart", map[string]any{"instance": instanceName, "keys": restartKeys})
+			requireInstanceRestart = true
 		}
 
 		// Stop the instance if it's planned to be stopped or if the restart is required.
-		if !plan.Running.ValueBool() || requireInstanceRestart {
-			// If the instance is currently running and is not planned to be stopped,
-			// we need to reject the update in case the provider is not allowed to
-			// temporarily stop the instance. Otherwise, we could render the instance
-			// unavailable without user's permission.
-			if plan.Running.ValueBool() && !plan.AllowRestart.ValueBool() {
-				resp.Diagnostics.AddError(
-					"Instance stop not allowed",
-					fmt.Sprintf(`The provider must temporarily stop the instance %q for migration or renaming, but stopping is not allowed. Either stop the instance manually or set the "allow_restart" attribute to "true".`, instanceName),
-				)
-				return
-			}
+		requireInstanceStop = desiredState == instanceStateStopped || requireInstanceRestart
 
-			_, diag := stopInstance(ctx, server, instanceName, false)
-			if diag != nil {
-				resp.Diagnostics.Append(diag)
-				return
-			}
+		// If the instance is currently running and is not planned to be stopped,
+		// we need to reject the update in case the provider is not allowed to
+		// temporarily stop the instance. Otherwise, we could render the instance
+		// unavailable without user's permission.
+		if requireInstanceStop && desiredState != instanceStateStopped && !plan.AllowRestart.ValueBool() {
+			resp.Diagnostics.AddError(
+				"Instance stop not allowed",
+				fmt.Sprintf(`The provider must temporarily stop the instance %q for migration, renaming, or applying changes that cannot be applied live, but stopping is not allowed. Either stop the instance manually or set the "allow_restart" attribute to "true".`, instanceName),
+			)
+			return
+		}
+	}
 
-			// Refresh instance data and etag after stop.
-			instance, etag, err = server.GetInstance(instanceName)
-			if err != nil {
-				resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve existing instance %q", instanceName), err.Error())
+	snapshotBeforeUpdate, diags := ToSnapshotBeforeUpdate(ctx, plan.SnapshotBeforeUpdate)
+	if diags.HasError() {
+		resp.Diagnostics.Append(diags...)
+		return
+	}
+
+	// Snapshot the instance before it is modified. If any of the following
+	// steps fails, the instance is restored from the snapshot.
+	if snapshotBeforeUpdate != nil && snapshotBeforeUpdate.Enabled.ValueBool() {
+		isDisruptive, diags := isDisruptiveUpdate(ctx, plan, state, requireInstanceMigration)
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+
+		if isDisruptive {
+			// Stateful snapshot can only be taken of a running instance.
+			stateful := snapshotBeforeUpdate.Stateful.ValueBool() && !instanceStopped
+
+			snapshotName, diag := createPreUpdateSnapshot(ctx, server, instanceName, stateful)
+			if diag != nil {
+				resp.Diagnostics.Append(diag)
 				return
 			}
 
-			instanceStopped = true
+			// Record the original status, so it can be restored.
+			status := instanceState.StatusCode
+
+			defer func() {
+				if !resp.Diagnostics.HasError() {
+					return
+				}
+
+				// The update may have failed due to the exceeded timeout,
+				// therefore use a fresh context for the rollback.
+				rollbackCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), r.provider.DefaultTimeout())
+				defer cancel()
+
+				// Use instanceName that is in scope, as the instance may
+				// have been renamed in the meantime.
+				resp.Diagnostics.Append(restorePreUpdateSnapshot(rollbackCtx, server, instanceName, snapshotName, stateful, status)...)
+			}()
+		}
+	}
+
+	// Unfreeze the instance, so it can be updated, stopped, or
+	// accessed by the provider. It is frozen again at the end of
+	// the update if requested.
+	if instanceState.StatusCode == api.Frozen {
+		diag := unfreezeInstance(ctx, server, instanceName)
+		if diag != nil {
+			resp.Diagnostics.Append(diag)
+			return
 		}
 	}
 
+	// Ensure instance is stopped if required.
+	if requireInstanceStop {
+		var diag diag.Diagnostic
+		stateful := plan.StatefulStop.ValueBool()
+
+		stopTimeout, ok := plan.StopTimeoutDuration()
+		if ok {
+			// Force the instance to stop if it does not stop gracefully in time.
+			_, diag = gracefulStopInstance(ctx, server, instanceName, stopTimeout, true, stateful)
+		} else {
+			_, diag = stopInstance(ctx, server, instanceName, false, stateful)
+		}
+
+		if diag != nil {
+			resp.Diagnostics.Append(diag)
+			return
+		}
+
+		// Refresh instance data and etag after stop.
+		instance, etag, err = server.GetInstance(instanceName)
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve existing instance %q", instanceName), err.Error())
+			return
+		}
+
+		instanceStopped = true
+	}
+
 	for _, device := range devices {
 		// Mark the device as managed by terraform to differentiate between
 		// devices added by terraform and devices added manually.
@@ -1110,7 +1603,7 @@ func (r InstanceResource) Update(ctx context.Context, req resource.UpdateRequest
 	}
 
 	// Ensure the instance is started if needed.
-	if plan.Running.ValueBool() && instanceStopped {
+	if desiredState != instanceStateStopped && instanceStopped {
 		instanceStarted = true
 		instanceStopped = false
 
@@ -1140,10 +1633,13 @@ func (r InstanceResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
-	// Remove files that are no longer present in newFiles.
-	for _, f := range oldFiles {
-		targetPath := f.TargetPath.ValueString()
-		err := common.InstanceFileDelete(server, instanceName, targetPath)
+	// Remove old files in reverse order of their target paths, which
+	// ensures files within directories are removed first.
+	oldPaths := utils.SortMapKeys(oldFiles)
+	slices.Reverse(oldPaths)
+
+	for _, targetPath := range oldPaths {
+		err := common.InstanceFileDelete(ctx, server, instanceName, targetPath)
 		if err != nil {
 			resp.Diagnostics.AddError(fmt.Sprintf("Failed to delete file from instance %q", instanceName), err.Error())
 			return
@@ -1151,8 +1647,8 @@ func (r InstanceResource) Update(ctx context.Context, req resource.UpdateRequest
 	}
 
 	// Upload new files.
-	for _, f := range newFiles {
-		err := common.InstanceFileUpload(server, instanceName, f)
+	for _, targetPath := range utils.SortMapKeys(newFiles) {
+		err := common.InstanceFileUpload(server, instanceName, newFiles[targetPath])
 		if err != nil {
 			resp.Diagnostics.AddError(fmt.Sprintf("Failed to upload file to instance %q", instanceName), err.Error())
 			return
@@ -1195,6 +1691,24 @@ func (r InstanceResource) Update(ctx context.Context, req resource.UpdateRequest
 		return
 	}
 
+	// Freeze the instance if requested.
+	if desiredState == instanceStateFrozen && !instanceStopped {
+		diag := freezeInstance(ctx, server, instanceName)
+		if diag != nil {
+			resp.Diagnostics.Append(diag)
+			return
+		}
+	}
+
+	// Remove pre-update snapshots that exceed the retention limit.
+	if snapshotBeforeUpdate != nil && snapshotBeforeUpdate.Enabled.ValueBool() {
+		keep := int(snapshotBeforeUpdate.Keep.ValueInt64())
+		err := prunePreUpdateSnapshots(ctx, server, instanceName, keep)
+		if err != nil {
+			resp.Diagnostics.AddWarning(fmt.Sprintf("Failed to remove old pre-update snapshots of instance %q", instanceName), err.Error())
+		}
+	}
+
 	// Update Terraform state.
 	diags = r.SyncState(ctx, &resp.State, server, plan)
 	resp.Diagnostics.Append(diags...)
@@ -1229,8 +1743,25 @@ func (r InstanceResource) Delete(ctx context.Context, req resource.DeleteRequest
 
 	instanceName := state.Name.ValueString()
 
-	// Force stop the instance, because we are deleting it anyway.
-	isFound, diag := stopInstance(ctx, server, instanceName, true)
+	var isFound bool
+	var diag diag.Diagnostic
+
+	forceStop := state.ForceStopOnDelete.IsNull() || state.ForceStopOnDelete.ValueBool()
+	stopTimeout, ok := state.StopTimeoutDuration()
+	if !ok && forceStop {
+		// Force stop the instance, because we are deleting it anyway.
+		tflog.Debug(ctx, "Forcefully stopping instance before deletion", map[string]any{"instance": instanceName})
+		isFound, diag = stopInstance(ctx, server, instanceName, true, false)
+	} else {
+		if !ok {
+			stopTimeout = 3 * time.Minute
+		}
+
+		// Attempt to stop the instance gracefully, and force it to
+		// stop only if allowed.
+		isFound, diag = gracefulStopInstance(ctx, server, instanceName, stopTimeout, forceStop, false)
+	}
+
 	if diag != nil {
 		// Ephemeral instances will be removed when stopped.
 		if !isFound {
@@ -1413,6 +1944,15 @@ func (r InstanceResource) SyncState(ctx context.Context, tfState *tfsdk.State, s
 	// does not match the expected one.
 	m.Running = types.BoolValue(instanceState.Status == api.Running.String())
 
+	switch {
+	case instanceState.StatusCode == api.Frozen:
+		m.State = types.StringValue(instanceStateFrozen)
+	case isInstanceRunning(*instanceState):
+		m.State = types.StringValue(instanceStateRunning)
+	default:
+		m.State = types.StringValue(instanceStateStopped)
+	}
+
 	m.Location = types.StringValue("")
 	if server.IsClustered() || instance.Location != "none" {
 		m.Location = types.StringValue(instance.Location)
@@ -1438,9 +1978,33 @@ func (r InstanceResource) SyncState(ctx context.Context, tfState *tfsdk.State, s
 		m.AllowRestart = types.BoolValue(false)
 	}
 
+	if m.StatefulStop.IsNull() {
+		m.StatefulStop = types.BoolValue(false)
+	}
+
+	if m.ForceStopOnDelete.IsNull() {
+		m.ForceStopOnDelete = types.BoolValue(true)
+	}
+
 	return tfState.Set(ctx, &m)
 }
 
+// StopTimeoutDuration returns the parsed stop timeout and true if the
+// stop timeout is set.
+func (m InstanceModel) StopTimeoutDuration() (time.Duration, bool) {
+	if m.StopTimeout.IsNull() || m.StopTimeout.IsUnknown() {
+		return 0, false
+	}
+
+	// Duration is validated beforehand.
+	timeout, err := time.ParseDuration(m.StopTimeout.ValueString())
+	if err != nil {
+		return 0, false
+	}
+
+	return timeout, true
+}
+
 // ComputedKeys returns list of computed config keys.
 func (m InstanceModel) ComputedKeys() []string {
 	return []string{
@@ -1495,7 +2059,7 @@ func startInstance(ctx context.Context, server lxd.InstanceServer, instanceName
 	}
 
 	if err != nil {
-		return diag.NewErrorDiagnostic(fmt.Sprintf("Failed to start instance %q", instanceName), err.Error())
+		return diag.NewErrorDiagnostic(fmt.Sprintf("Failed to start instance %q", instanceName), waitErrorDetail(ctx, server, instanceName, err))
 	}
 
 	instanceStartedCheck := func() (any, string, error) {
@@ -1511,7 +2075,7 @@ func startInstance(ctx context.Context, server lxd.InstanceServer, instanceName
 	// the instance is started via a new API call.
 	_, err = waitForState(ctx, instanceStartedCheck, api.Running.String(), api.Ready.String())
 	if err != nil {
-		return diag.NewErrorDiagnostic(fmt.Sprintf("Failed to wait for instance %q to start", instanceName), err.Error())
+		return diag.NewErrorDiagnostic(fmt.Sprintf("Failed to wait for instance %q to start", instanceName), waitErrorDetail(ctx, server, instanceName, err))
 	}
 
 	return nil
@@ -1520,8 +2084,9 @@ func startInstance(ctx context.Context, server lxd.InstanceServer, instanceName
 // stopInstance stops an instance with the given name. It waits for its
 // status to become Stopped or the instance to be removed (not found) in
 // case of an ephemeral instance. In the latter case, false is returned
-// along an error.
-func stopInstance(ctx context.Context, server lxd.InstanceServer, instanceName string, force bool) (bool, diag.Diagnostic) {
+// along an error. If stateful is true, the runtime state of the instance
+// is preserved and restored on the next start.
+func stopInstance(ctx context.Context, server lxd.InstanceServer, instanceName string, force bool, stateful bool) (bool, diag.Diagnostic) {
 	st, etag, err := server.GetInstanceState(instanceName)
 	if err != nil {
 		return true, diag.NewErrorDiagnostic(fmt.Sprintf("Failed to retrieve state of instance %q", instanceName), err.Error())
@@ -1533,9 +2098,10 @@ func stopInstance(ctx context.Context, server lxd.InstanceServer, instanceName s
 	}
 
 	stopReq := api.InstanceStatePut{
-		Action:  "stop",
-		Force:   force,
-		Timeout: utils.ContextTimeout(ctx, 3*time.Minute),
+		Action:   "stop",
+		Force:    force,
+		Stateful: stateful,
+		Timeout:  utils.ContextTimeout(ctx, 3*time.Minute),
 	}
 
 	// Stop the instance.
@@ -1568,6 +2134,93 @@ func stopInstance(ctx context.Context, server lxd.InstanceServer, instanceName s
 	return true, nil
 }
 
+// gracefulStopInstance attempts to stop an instance gracefully within the
+// given timeout. If the instance does not stop in time and force is true,
+// the instance is forcefully stopped. Similar to stopInstance, false is
+// returned if the instance has been removed in the meantime.
+func gracefulStopInstance(ctx context.Context, server lxd.InstanceServer, instanceName string, timeout time.Duration, force bool, stateful bool) (bool, diag.Diagnostic) {
+	stopCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	logFields := map[string]any{"instance": instanceName, "timeout": timeout.String()}
+
+	tflog.Debug(ctx, "Attempting to stop instance gracefully", logFields)
+	isFound, diag := stopInstance(stopCtx, server, instanceName, false, stateful)
+	if diag == nil {
+		tflog.Info(ctx, "Instance stopped gracefully", logFields)
+		return true, nil
+	}
+
+	if !isFound || !force {
+		return isFound, diag
+	}
+
+	tflog.Warn(ctx, "Instance did not stop gracefully, forcing it to stop", logFields)
+	isFound, diag = stopInstance(ctx, server, instanceName, true, false)
+	if diag == nil {
+		tflog.Info(ctx, "Instance stopped forcefully", logFields)
+	}
+
+	return isFound, diag
+}
+
+// freezeInstance freezes an instance with the given name and waits for
+// its status to become Frozen.
+func freezeInstance(ctx context.Context, server lxd.InstanceServer, instanceName string) diag.Diagnostic {
+	err := updateInstanceStatus(ctx, server, instanceName, "freeze", api.Frozen.String())
+	if err != nil {
+		return diag.NewErrorDiagnostic(fmt.Sprintf("Failed to freeze instance %q", instanceName), err.Error())
+	}
+
+	return nil
+}
+
+// unfreezeInstance unfreezes an instance with the given name and waits for
+// its status to become Running or Ready.
+func unfreezeInstance(ctx context.Context, server lxd.InstanceServer, instanceName string) diag.Diagnostic {
+	err := updateInstanceStatus(ctx, server, instanceName, "unfreeze", api.Running.String(), api.Ready.String())
+	if err != nil {
+		return diag.NewErrorDiagnostic(fmt.Sprintf("Failed to unfreeze instance %q", instanceName), err.Error())
+	}
+
+	return nil
+}
+
+// updateInstanceStatus performs the given state action on an instance and
+// waits until the instance reports one of the target statuses.
+func updateInstanceStatus(ctx context.Context, server lxd.InstanceServer, instanceName string, action string, targets ...string) error {
+	_, etag, err := server.GetInstanceState(instanceName)
+	if err != nil {
+		return err
+	}
+
+	req := api.InstanceStatePut{
+		Action:  action,
+		Timeout: utils.ContextTimeout(ctx, 3*time.Minute),
+	}
+
+	op, err := server.UpdateInstanceState(instanceName, req, etag)
+	if err == nil {
+		err = op.WaitContext(ctx)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	instanceStatusCheck := func() (any, string, error) {
+		st, _, err := server.GetInstanceState(instanceName)
+		if err != nil {
+			return st, "Error", err
+		}
+
+		return st, st.Status, nil
+	}
+
+	_, err = waitForState(ctx, instanceStatusCheck, targets...)
+	return err
+}
+
 // renameInstance renames an instance with the given old name to a new name.
 // Instance has to be stopped beforehand, otherwise the operation will fail.
 func renameInstance(ctx context.Context, server lxd.InstanceServer, oldName string, newName string) error {
@@ -1598,6 +2251,210 @@ func migrateInstance(ctx context.Context, server lxd.InstanceServer, instanceNam
 	return op.WaitContext(ctx)
 }
 
+// restartRequiredChanges returns config keys and device properties that are
+// changed between the old and new instance model, and cannot be applied to
+// the running instance according to the LXD configuration metadata. If the
+// server does not expose the configuration metadata, only the increase of
+// virtual machine memory is reported.
+func restartRequiredChanges(ctx context.Context, server lxd.InstanceServer, oldModel InstanceModel, newModel InstanceModel) ([]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	oldConfig, d := common.ToConfigMap(ctx, oldModel.Config)
+	diags.Append(d...)
+
+	newConfig, d := common.ToConfigMap(ctx, newModel.Config)
+	diags.Append(d...)
+
+	oldDevices, d := common.ToDeviceMap(ctx, oldModel.Devices)
+	diags.Append(d...)
+
+	newDevices, d := common.ToDeviceMap(ctx, newModel.Devices)
+	diags.Append(d...)
+
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	var keys []string
+	if server.HasExtension("metadata_configuration") {
+		meta, err := server.GetMetadataConfiguration()
+		if err != nil {
+			diags.AddError("Failed to retrieve LXD configuration metadata", err.Error())
+			return nil, diags
+		}
+
+		keys = common.RestartRequiredChanges(meta, newModel.Type.ValueString(), oldConfig, newConfig, oldDevices, newDevices)
+	}
+
+	// Currently memory for virtual machines cannot be live updated, even
+	// though the configuration metadata states otherwise. Restart the
+	// virtual machine if provider is allowed to stop the instance and the
+	// limit was increased. Otherwise, let LXD reject the change.
+	//
+	// XXX: Remove once https://github.com/canonical/lxd/issues/15010 is resolved.
+	if newModel.Type.ValueString() == string(api.InstanceTypeVM) && newModel.AllowRestart.ValueBool() && !slices.Contains(keys, "limits.memory") {
+		oldMemory, err := units.ParseByteSizeString(oldConfig["limits.memory"])
+		if err != nil {
+			diags.AddError("Failed to parse existing memory limit", err.Error())
+			return nil, diags
+		}
+
+		newMemory, err := units.ParseByteSizeString(newConfig["limits.memory"])
+		if err != nil {
+			diags.AddError("Failed to parse new memory limit", err.Error())
+			return nil, diags
+		}
+
+		if newMemory > oldMemory {
+			keys = append(keys, "limits.memory")
+		}
+	}
+
+	return keys, nil
+}
+
+// isDisruptiveUpdate determines whether the planned update modifies the
+// instance itself, and not only the provider specific attributes. Changes
+// of the description, the desired state ("running" and "state"), and
+// "wait_for" deliberately do not require a snapshot. They neither touch
+// the instance filesystem nor its configuration beyond metadata, and are
+// reverted by applying the previous configuration.
+func isDisruptiveUpdate(ctx context.Context, plan InstanceModel, state InstanceModel, requireMigration bool) (bool, diag.Diagnostics) {
+	if requireMigration ||
+		!plan.Name.Equal(state.Name) ||
+		!plan.Config.Equal(state.Config) ||
+		!plan.Profiles.Equal(state.Profiles) ||
+		!plan.Devices.Equal(state.Devices) ||
+		!plan.Files.Equal(state.Files) {
+		return true, nil
+	}
+
+	// Exec commands have computed attributes, therefore compare only
+	// whether any of the commands will be executed.
+	execs, diags := common.ToExecMap(ctx, plan.Execs)
+	if diags.HasError() {
+		return false, diags
+	}
+
+	for _, e := range execs {
+		if e.IsTriggered(false) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// createPreUpdateSnapshot creates a snapshot of the instance before it is
+// updated. The snapshot name contains a timestamp with microsecond
+// precision, which ensures that the snapshots of consecutive updates do
+// not collide and can be sorted by their creation time. Name of the
+// created snapshot is returned.
+func createPreUpdateSnapshot(ctx context.Context, server lxd.InstanceServer, instanceName string, stateful bool) (string, diag.Diagnostic) {
+	timestamp := strings.Replace(time.Now().UTC().Format("20060102-150405.000000"), ".", "-", 1)
+	snapshotName := preUpdateSnapshotPrefix + timestamp
+
+	req := api.InstanceSnapshotsPost{
+		Name:     snapshotName,
+		Stateful: stateful,
+	}
+
+	op, err := server.CreateInstanceSnapshot(instanceName, req)
+	if err == nil {
+		err = op.WaitContext(ctx)
+	}
+
+	if err != nil {
+		return "", diag.NewErrorDiagnostic(fmt.Sprintf("Failed to create pre-update snapshot %q of instance %q", snapshotName, instanceName), err.Error())
+	}
+
+	return snapshotName, nil
+}
+
+// restorePreUpdateSnapshot restores the instance from the pre-update
+// snapshot. If the instance was running or frozen before the update, it
+// is started, and frozen again if needed, once the snapshot is restored.
+func restorePreUpdateSnapshot(ctx context.Context, server lxd.InstanceServer, instanceName string, snapshotName string, stateful bool, status api.StatusCode) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	tflog.Warn(ctx, "Instance update failed, restoring pre-update snapshot", map[string]any{"instance": instanceName, "snapshot": snapshotName})
+
+	req := api.InstancePut{
+		Restore:  snapshotName,
+		Stateful: stateful,
+	}
+
+	op, err := server.UpdateInstance(instanceName, req, "")
+	if err == nil {
+		err = op.WaitContext(ctx)
+	}
+
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Failed to restore instance %q from pre-update snapshot %q", instanceName, snapshotName), err.Error())
+		return diags
+	}
+
+	if status != api.Stopped {
+		diag := startInstance(ctx, server, instanceName)
+		if diag != nil {
+			diags.Append(diag)
+			return diags
+		}
+	}
+
+	if status == api.Frozen {
+		diag := freezeInstance(ctx, server, instanceName)
+		if diag != nil {
+			diags.Append(diag)
+			return diags
+		}
+	}
+
+	diags.AddWarning(
+		fmt.Sprintf("Instance %q restored from pre-update snapshot", instanceName),
+		fmt.Sprintf("The instance update failed, therefore the instance was restored from the snapshot %q.", snapshotName),
+	)
+
+	return diags
+}
+
+// prunePreUpdateSnapshots removes the oldest pre-update snapshots of the
+// instance, so that at most keep snapshots are retained.
+func prunePreUpdateSnapshots(ctx context.Context, server lxd.InstanceServer, instanceName string, keep int) error {
+	names, err := server.GetInstanceSnapshotNames(instanceName)
+	if err != nil {
+		return err
+	}
+
+	snapshots := make([]string, 0, len(names))
+	for _, name := range names {
+		if strings.HasPrefix(name, preUpdateSnapshotPrefix) {
+			snapshots = append(snapshots, name)
+		}
+	}
+
+	if len(snapshots) <= keep {
+		return nil
+	}
+
+	// Snapshot names contain a timestamp, therefore sorting them
+	// alphabetically also sorts them from oldest to newest.
+	slices.Sort(snapshots)
+
+	for _, name := range snapshots[:len(snapshots)-keep] {
+		op, err := server.DeleteInstanceSnapshot(instanceName, name, "")
+		if err == nil {
+			err = op.WaitContext(ctx)
+		}
+
+		if err != nil && !errors.IsNotFoundError(err) {
+			return fmt.Errorf("Failed to remove snapshot %q: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
 // waitFor waits for the instance with the given name to reach the desired
 // state. It returns an error if the instance does not reach the desired
 // state within the given timeout.
@@ -1624,6 +2481,17 @@ func waitFor(ctx context.Context, server lxd.InstanceServer, instanceName string
 			d = waitForInstanceNetwork(ctx, server, instanceName, waitForType, nic)
 		case "ready":
 			d = waitForInstanceToBeReady(ctx, server, instanceName)
+		case "cloud-init":
+			d = waitForInstanceCloudInit(ctx, server, instanceName)
+		case "exec":
+			d = waitForInstanceExec(ctx, server, instanceName, waitForModel)
+		case "file":
+			filePath := waitForModel.Path.ValueString()
+			d = waitForInstanceFile(ctx, server, instanceName, filePath)
+		case "port":
+			port := waitForModel.Port.ValueInt64()
+			nic := waitForModel.Nic.ValueString()
+			d = waitForInstancePort(ctx, server, instanceName, port, nic)
 		default:
 			d.AddError(fmt.Sprintf("Invalid value for wait_for: %q", waitForType), "")
 		}
@@ -1663,7 +2531,7 @@ func waitForInstanceAgent(ctx context.Context, server lxd.InstanceServer, instan
 	err := waitForInstanceCondition(ctx, server, instanceName, isInstanceOperational)
 	if err != nil {
 		var diags diag.Diagnostics
-		diags.AddError(fmt.Sprintf("Failed to wait for instance %q agent to be ready", instanceName), err.Error())
+		diags.AddError(fmt.Sprintf("Failed to wait for instance %q agent to be ready", instanceName), waitErrorDetail(ctx, server, instanceName, err))
 		return diags
 	}
 
@@ -1733,7 +2601,7 @@ func waitForInstanceNetwork(ctx context.Context, server lxd.InstanceServer, inst
 	err := waitForInstanceCondition(ctx, server, instanceName, condition)
 	if err != nil {
 		var diags diag.Diagnostics
-		diags.AddError(fmt.Sprintf("Failed to wait for instance %q to get an IP address", instanceName), err.Error())
+		diags.AddError(fmt.Sprintf("Failed to wait for instance %q to get an IP address", instanceName), waitErrorDetail(ctx, server, instanceName, err))
 		return diags
 	}
 
@@ -1745,13 +2613,258 @@ func waitForInstanceToBeReady(ctx context.Context, server lxd.InstanceServer, in
 	err := waitForInstanceCondition(ctx, server, instanceName, isInstanceReady)
 	if err != nil {
 		var diags diag.Diagnostics
-		diags.AddError(fmt.Sprintf("Failed to wait for instance %q to be ready", instanceName), err.Error())
+		diags.AddError(fmt.Sprintf("Failed to wait for instance %q to be ready", instanceName), waitErrorDetail(ctx, server, instanceName, err))
 		return diags
 	}
 
 	return nil
 }
 
+// waitForInstanceCloudInit waits for cloud-init to finish within the instance.
+// Command "cloud-init status --wait" is retried until it can be executed, as
+// the instance (or the LXD agent in case of a virtual machine) may not be
+// ready to execute commands yet.
+func waitForInstanceCloudInit(ctx context.Context, server lxd.InstanceServer, instanceName string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	cmd := []string{"cloud-init", "status", "--wait"}
+
+	for {
+		exitCode, err := execInstanceCommand(ctx, server, instanceName, cmd)
+		if err == nil {
+			switch exitCode {
+			case 0:
+				return nil
+			case 2:
+				// Cloud-init has finished with recoverable errors.
+				tflog.Warn(ctx, "Cloud-init finished with recoverable errors", map[string]any{"instance": instanceName})
+				return nil
+			default:
+				diags.AddError(
+					fmt.Sprintf("Cloud-init failed within instance %q", instanceName),
+					fmt.Sprintf("Command %q exited with code %d.", strings.Join(cmd, " "), exitCode),
+				)
+				return diags
+			}
+		}
+
+		tflog.Debug(ctx, "Failed to execute cloud-init status command, retrying", map[string]any{"instance": instanceName, "error": err.Error()})
+
+		select {
+		case <-time.After(5 * time.Second):
+		case <-ctx.Done():
+			diags.AddError(fmt.Sprintf("Failed to wait for cloud-init to finish within instance %q", instanceName), waitErrorDetail(ctx, server, instanceName, err))
+			return diags
+		}
+	}
+}
+
+// waitForInstanceExec executes the wait_for command within the instance until
+// it exits successfully. The command is retried in the configured interval
+// (defaults to 5s), either the configured number of times or until the
+// context is done.
+func waitForInstanceExec(ctx context.Context, server lxd.InstanceServer, instanceName string, waitForModel WaitForModel) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	cmd := make([]string, 0, len(waitForModel.Command.Elements()))
+	diags.Append(waitForModel.Command.ElementsAs(ctx, &cmd, false)...)
+	if diags.HasError() {
+		return diags
+	}
+
+	interval := 5 * time.Second
+	if waitForModel.Interval.ValueString() != "" {
+		var err error
+		interval, err = time.ParseDuration(waitForModel.Interval.ValueString())
+		if err != nil {
+			diags.AddError(fmt.Sprintf("Failed to parse interval duration for instance %q", instanceName), err.Error())
+			return diags
+		}
+	}
+
+	// Retry until the context is done, unless retries are set.
+	retries := int64(-1)
+	if !waitForModel.Retries.IsNull() {
+		retries = waitForModel.Retries.ValueInt64()
+	}
+
+	for attempt := int64(0); ; attempt++ {
+		exitCode, err := execInstanceCommand(ctx, server, instanceName, cmd)
+		if err == nil && exitCode == 0 {
+			return nil
+		}
+
+		if err == nil {
+			err = fmt.Errorf("Command %q exited with code %d", strings.Join(cmd, " "), exitCode)
+		}
+
+		if retries >= 0 && attempt >= retries {
+			diags.AddError(fmt.Sprintf("Failed to wait for command to succeed within instance %q", instanceName), err.Error())
+			return diags
+		}
+
+		tflog.Debug(ctx, "Wait for command failed, retrying", map[string]any{"instance": instanceName, "error": err.Error()})
+
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			diags.AddError(fmt.Sprintf("Failed to wait for command to succeed within instance %q", instanceName), waitErrorDetail(ctx, server, instanceName, err))
+			return diags
+		}
+	}
+}
+
+// waitForInstanceFile waits for the file at the given path to exist within
+// the instance.
+func waitForInstanceFile(ctx context.Context, server lxd.InstanceServer, instanceName string, filePath string) diag.Diagnostics {
+	check := func() (any, string, error) {
+		content, _, err := server.GetInstanceFile(instanceName, filePath)
+		if err != nil {
+			// File does not exist yet or the instance cannot serve
+			// files yet.
+			return nil, "Waiting", nil
+		}
+
+		// Content is not returned for directories.
+		if content != nil {
+			_ = content.Close()
+		}
+
+		return filePath, "OK", nil
+	}
+
+	_, err := waitForState(ctx, check, "OK")
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError(fmt.Sprintf("Failed to wait for file %q to exist within instance %q", filePath, instanceName), waitErrorDetail(ctx, server, instanceName, err))
+		return diags
+	}
+
+	return nil
+}
+
+// waitForInstancePort waits for the given TCP port to be open on the
+// instance's IP address. The IP address is determined the same way as
+// for the "ipv4"/"ipv6" wait_for types, preferring IPv4 over IPv6.
+func waitForInstancePort(ctx context.Context, server lxd.InstanceServer, instanceName string, port int64, nic string) diag.Diagnostics {
+	if nic == "" {
+		inst, _, err := server.GetInstance(instanceName)
+		if err == nil {
+			accIface, ok := inst.ExpandedConfig["user.access_interface"]
+			if ok {
+				nic = accIface
+			}
+		}
+	}
+
+	check := func() (any, string, error) {
+		state, _, err := server.GetInstanceState(instanceName)
+		if err != nil {
+			return state, "Error", err
+		}
+
+		for _, iface := range utils.SortMapKeys(state.Network) {
+			if iface == "lo" || (nic != "" && nic != iface) {
+				continue
+			}
+
+			ipv4, ipv6 := findGlobalIPAddresses(state.Network[iface])
+			for _, ip := range []string{ipv4, ipv6} {
+				if ip == "" {
+					continue
+				}
+
+				addr := net.JoinHostPort(ip, strconv.FormatInt(port, 10))
+				conn, err := net.DialTimeout("tcp", addr, 2*time.Second)
+				if err == nil {
+					_ = conn.Close()
+					return addr, "OK", nil
+				}
+			}
+		}
+
+		return state, "Waiting", nil
+	}
+
+	_, err := waitForState(ctx, check, "OK")
+	if err != nil {
+		var diags diag.Diagnostics
+		diags.AddError(fmt.Sprintf("Failed to wait for port %d to be open on instance %q", port, instanceName), waitErrorDetail(ctx, server, instanceName, err))
+		return diags
+	}
+
+	return nil
+}
+
+// execInstanceCommand executes the given command within the instance and
+// returns its exit code. The command output is discarded.
+func execInstanceCommand(ctx context.Context, server lxd.InstanceServer, instanceName string, cmd []string) (int, error) {
+	req := api.InstanceExecPost{
+		Command:     cmd,
+		WaitForWS:   true,
+		Interactive: false,
+	}
+
+	args := lxd.InstanceExecArgs{
+		Stdout:   utils.NewDiscardCloser(),
+		Stderr:   utils.NewDiscardCloser(),
+		DataDone: make(chan bool),
+	}
+
+	op, err := server.ExecInstance(instanceName, req, &args)
+	if err != nil {
+		return -1, err
+	}
+
+	err = op.WaitContext(ctx)
+	if err != nil {
+		return -1, err
+	}
+
+	// Wait for any remaining output to be flushed.
+	select {
+	case <-ctx.Done():
+		return -1, ctx.Err()
+	case <-args.DataDone:
+	}
+
+	rc, ok := op.Get().Metadata["return"].(float64)
+	if !ok {
+		return -1, fmt.Errorf("Failed to retrieve exit code of command %q", strings.Join(cmd, " "))
+	}
+
+	return int(rc), nil
+}
+
+// waitErrorDetail returns the detail of an error that occurred while waiting
+// for the instance. If waiting was interrupted by a timeout, the tail of the
+// instance console log is appended, as it usually reveals why the instance
+// did not become operational.
+func waitErrorDetail(ctx context.Context, server lxd.InstanceServer, instanceName string, err error) string {
+	detail := err.Error()
+
+	_, isTimeout := err.(*retry.TimeoutError)
+	if !isTimeout && ctx.Err() == nil {
+		return detail
+	}
+
+	consoleLog, logErr := common.InstanceConsoleLog(server, instanceName)
+	if logErr != nil {
+		tflog.Debug(ctx, "Failed to retrieve instance console log", map[string]any{"instance": instanceName, "error": logErr.Error()})
+		return detail
+	}
+
+	tail := common.TailLines(consoleLog, consoleLogTailLines)
+	if tail == "" {
+		return detail
+	}
+
+	// The console log may contain fewer lines than requested.
+	lines := strings.Count(tail, "\n") + 1
+
+	return fmt.Sprintf("%s\n\nInstance console log (last %d lines):\n%s", detail, lines, tail)
+}
+
 // waitForState waits until the provided function reports one of the target
 // states. It returns either the resulting state or an error.
 func waitForState(ctx context.Context, refreshFunc retry.StateRefreshFunc, targets ...string) (any, error) {
@@ -1858,3 +2971,19 @@ func ToWaitForList(ctx context.Context, waitForSet types.Set) ([]WaitForModel, d
 
 	return waitForConfigs, nil
 }
+
+// ToSnapshotBeforeUpdate converts snapshot_before_update from types.Object
+// into SnapshotBeforeUpdateModel. If the block is not set, nil is returned.
+func ToSnapshotBeforeUpdate(ctx context.Context, obj types.Object) (*SnapshotBeforeUpdateModel, diag.Diagnostics) {
+	if obj.IsNull() || obj.IsUnknown() {
+		return nil, nil
+	}
+
+	var m SnapshotBeforeUpdateModel
+	diags := obj.As(ctx, &m, basetypes.ObjectAsOptions{})
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	return &m, nil
+}
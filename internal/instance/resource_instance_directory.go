@@ -0,0 +1,568 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+
+	lxd "github.com/canonical/lxd/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/common"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
+	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/utils"
+)
+
+// InstanceDirectoryModel represents a local directory synced into
+// an LXD instance.
+type InstanceDirectoryModel struct {
+	Instance      types.String `tfsdk:"instance"`
+	SourcePath    types.String `tfsdk:"source_path"`
+	TargetPath    types.String `tfsdk:"target_path"`
+	Include       types.List   `tfsdk:"include"`
+	Exclude       types.List   `tfsdk:"exclude"`
+	UserID        types.Int64  `tfsdk:"uid"`
+	GroupID       types.Int64  `tfsdk:"gid"`
+	FileMode      types.String `tfsdk:"file_mode"`
+	DirectoryMode types.String `tfsdk:"directory_mode"`
+	DeleteRemoved types.Bool   `tfsdk:"delete_removed"`
+	Project       types.String `tfsdk:"project"`
+	Remote        types.String `tfsdk:"remote"`
+
+	// Computed.
+	Files types.Map `tfsdk:"files"`
+}
+
+// InstanceDirectoryResource represent LXD instance directory resource.
+type InstanceDirectoryResource struct {
+	provider *provider_config.LxdProviderConfig
+}
+
+// NewInstanceDirectoryResource returns a new instance directory resource.
+func NewInstanceDirectoryResource() resource.Resource {
+	return &InstanceDirectoryResource{}
+}
+
+func (r InstanceDirectoryResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_instance_directory"
+}
+
+func (r InstanceDirectoryResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	modeValidator := stringvalidator.RegexMatches(regexp.MustCompile(`^0?[0-7]{3}$`), "must be an octal file mode, e.g. \"0644\"")
+
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"instance": schema.StringAttribute{
+				Description: "Name of the instance",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"source_path": schema.StringAttribute{
+				Description: "Path to the local directory",
+				Required:    true,
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"target_path": schema.StringAttribute{
+				Description: "Absolute path of the directory within the instance",
+				Required:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`^/`), "must be an absolute path"),
+				},
+			},
+
+			"include": schema.ListAttribute{
+				Description: "Glob patterns of files to include",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+
+			"exclude": schema.ListAttribute{
+				Description: "Glob patterns of files and directories to exclude",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+
+			"uid": schema.Int64Attribute{
+				Description: "The user ID of uploaded files and directories",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(0),
+			},
+
+			"gid": schema.Int64Attribute{
+				Description: "The group ID of uploaded files and directories",
+				Optional:    true,
+				Computed:    true,
+				Default:     int64default.StaticInt64(0),
+			},
+
+			"file_mode": schema.StringAttribute{
+				Description: "The octal permissions of uploaded files",
+				Optional:    true,
+				Validators: []validator.String{
+					modeValidator,
+				},
+			},
+
+			"directory_mode": schema.StringAttribute{
+				Description: "The octal permissions of created directories",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString("0755"),
+				Validators: []validator.String{
+					modeValidator,
+				},
+			},
+
+			"delete_removed": schema.BoolAttribute{
+				Description: "Whether to delete files from the instance when they are removed from the source directory",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+			},
+
+			"project": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(provider_config.DefaultProject),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"remote": schema.StringAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			// Computed.
+
+			"files": schema.MapAttribute{
+				Description: "Map of synced files and their SHA-256 checksums",
+				Computed:    true,
+				ElementType: types.StringType,
+			},
+		},
+	}
+}
+
+func (r *InstanceDirectoryResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := req.ProviderData
+	if data == nil {
+		return
+	}
+
+	provider, ok := data.(*provider_config.LxdProviderConfig)
+	if !ok {
+		resp.Diagnostics.Append(errors.NewProviderDataTypeError(req.ProviderData))
+		return
+	}
+
+	r.provider = provider
+}
+
+func (r InstanceDirectoryResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config InstanceDirectoryModel
+
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	patterns := map[string]types.List{
+		"include": config.Include,
+		"exclude": config.Exclude,
+	}
+
+	for _, attr := range utils.SortMapKeys(patterns) {
+		for i, v := range patterns[attr].Elements() {
+			pattern, ok := v.(types.String)
+			if !ok || pattern.IsNull() || pattern.IsUnknown() {
+				continue
+			}
+
+			err := common.ValidateGlob(pattern.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root(attr).AtListIndex(i), "Invalid Configuration", err.Error())
+			}
+		}
+	}
+}
+
+// ModifyPlan computes checksums of the files within the local directory.
+// This ensures the update is planned whenever any of the local files is
+// added, removed, or modified.
+func (r InstanceDirectoryResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan InstanceDirectoryModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.SourcePath.IsUnknown() || plan.Include.IsUnknown() || plan.Exclude.IsUnknown() {
+		plan.Files = types.MapUnknown(types.StringType)
+	} else {
+		files, diags := r.localFiles(ctx, plan)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		plan.Files, diags = types.MapValueFrom(ctx, types.StringType, files)
+		resp.Diagnostics.Append(diags...)
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
+func (r InstanceDirectoryResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan InstanceDirectoryModel
+
+	// Fetch resource model from Terraform plan.
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := plan.Remote.ValueString()
+	project := plan.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	// Ensure instance exists.
+	instanceName := plan.Instance.ValueString()
+	_, _, err = server.GetInstance(instanceName)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed retrieve instance %q", instanceName), err.Error())
+		return
+	}
+
+	files, diags := r.plannedFiles(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = r.uploadFiles(server, plan, utils.SortMapKeys(files))
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.Files, diags = types.MapValueFrom(ctx, types.StringType, files)
+	resp.Diagnostics.Append(diags...)
+
+	// Update Terraform state.
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Read computes checksums of the previously synced files within the
+// instance. Files that were removed or modified within the instance
+// are therefore uploaded again on the next apply.
+func (r InstanceDirectoryResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state InstanceDirectoryModel
+
+	// Fetch resource model from Terraform state.
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := state.Remote.ValueString()
+	project := state.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	// Ensure instance exists.
+	instanceName := state.Instance.ValueString()
+	_, _, err = server.GetInstance(instanceName)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			// If instance is not found, files cannot exist. Remove the
+			// directory from the state to ensure it is synced on next apply.
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed retrieve instance %q", instanceName), err.Error())
+		return
+	}
+
+	stateFiles := make(map[string]string, len(state.Files.Elements()))
+	diags = state.Files.ElementsAs(ctx, &stateFiles, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	instanceFiles, err := common.InstanceDirectoryChecksums(server, instanceName, state.TargetPath.ValueString(), utils.SortMapKeys(stateFiles))
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve files from instance %q", instanceName), err.Error())
+		return
+	}
+
+	// Files that are missing or modified within the instance
+	// are uploaded again on the next apply.
+	state.Files, diags = types.MapValueFrom(ctx, types.StringType, instanceFiles)
+	resp.Diagnostics.Append(diags...)
+
+	// Update Terraform state.
+	diags = resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Update uploads new and modified files, and deletes files that were
+// removed from the source directory (if enabled). If ownership or
+// permissions have changed, all files are uploaded again.
+func (r InstanceDirectoryResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan InstanceDirectoryModel
+	var state InstanceDirectoryModel
+
+	// Fetch resource model from Terraform plan and state.
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := plan.Remote.ValueString()
+	project := plan.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	stateFiles := make(map[string]string, len(state.Files.Elements()))
+	diags := state.Files.ElementsAs(ctx, &stateFiles, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	files, diags := r.plannedFiles(ctx, plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// Re-upload all files if their ownership or permissions change.
+	reupload := !plan.UserID.Equal(state.UserID) ||
+		!plan.GroupID.Equal(state.GroupID) ||
+		!plan.FileMode.Equal(state.FileMode) ||
+		!plan.DirectoryMode.Equal(state.DirectoryMode) ||
+		!plan.SourcePath.Equal(state.SourcePath)
+
+	var changed []string
+	var removed []string
+
+	for _, rel := range utils.SortMapKeys(files) {
+		sum, ok := stateFiles[rel]
+		if reupload || !ok || sum != files[rel] {
+			changed = append(changed, rel)
+		}
+	}
+
+	for _, rel := range utils.SortMapKeys(stateFiles) {
+		_, ok := files[rel]
+		if !ok {
+			removed = append(removed, rel)
+		}
+	}
+
+	diags = r.uploadFiles(server, plan, changed)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.DeleteRemoved.ValueBool() {
+		diags = r.deleteFiles(ctx, server, plan, removed)
+		resp.Diagnostics.Append(diags...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	plan.Files, diags = types.MapValueFrom(ctx, types.StringType, files)
+	resp.Diagnostics.Append(diags...)
+
+	// Update Terraform state.
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete removes synced files from the instance, along with any
+// directories that are left empty.
+func (r InstanceDirectoryResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state InstanceDirectoryModel
+
+	// Fetch resource model from Terraform state.
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := state.Remote.ValueString()
+	project := state.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	// Ensure instance exists.
+	instanceName := state.Instance.ValueString()
+	_, _, err = server.GetInstance(instanceName)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return
+		}
+
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed retrieve instance %q", instanceName), err.Error())
+		return
+	}
+
+	stateFiles := make(map[string]string, len(state.Files.Elements()))
+	diags = state.Files.ElementsAs(ctx, &stateFiles, false)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	diags = r.deleteFiles(ctx, server, state, utils.SortMapKeys(stateFiles))
+	resp.Diagnostics.Append(diags...)
+}
+
+// localFiles returns checksums of the files within the local source
+// directory that match the configured include and exclude patterns.
+func (r InstanceDirectoryResource) localFiles(ctx context.Context, m InstanceDirectoryModel) (map[string]string, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	include := make([]string, 0, len(m.Include.Elements()))
+	exclude := make([]string, 0, len(m.Exclude.Elements()))
+
+	diags.Append(m.Include.ElementsAs(ctx, &include, false)...)
+	diags.Append(m.Exclude.ElementsAs(ctx, &exclude, false)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	files, err := common.LocalDirectoryFiles(m.SourcePath.ValueString(), include, exclude)
+	if err != nil {
+		diags.AddAttributeError(path.Root("source_path"), "Failed to read source directory", err.Error())
+		return nil, diags
+	}
+
+	return files, diags
+}
+
+// plannedFiles returns checksums of the local files, and ensures they
+// did not change since the plan was created.
+func (r InstanceDirectoryResource) plannedFiles(ctx context.Context, plan InstanceDirectoryModel) (map[string]string, diag.Diagnostics) {
+	files, diags := r.localFiles(ctx, plan)
+	if diags.HasError() || plan.Files.IsUnknown() || plan.Files.IsNull() {
+		return files, diags
+	}
+
+	plannedFiles := make(map[string]string, len(plan.Files.Elements()))
+	diags.Append(plan.Files.ElementsAs(ctx, &plannedFiles, false)...)
+	if diags.HasError() {
+		return nil, diags
+	}
+
+	if !maps.Equal(files, plannedFiles) {
+		diags.AddAttributeError(
+			path.Root("source_path"),
+			"Source directory changed",
+			fmt.Sprintf("Files within the source directory %q were modified after the plan was created. Please re-run the plan.", plan.SourcePath.ValueString()),
+		)
+		return nil, diags
+	}
+
+	return files, diags
+}
+
+// uploadFiles uploads the given files from the source directory into
+// the instance.
+func (r InstanceDirectoryResource) uploadFiles(server lxd.InstanceServer, m InstanceDirectoryModel, files []string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	instanceName := m.Instance.ValueString()
+	targetDir := m.TargetPath.ValueString()
+
+	err := common.InstanceDirectoryUpload(
+		server,
+		instanceName,
+		m.SourcePath.ValueString(),
+		targetDir,
+		files,
+		m.UserID.ValueInt64(),
+		m.GroupID.ValueInt64(),
+		m.FileMode.ValueString(),
+		m.DirectoryMode.ValueString(),
+	)
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Failed to sync directory %q on instance %q", targetDir, instanceName), err.Error())
+	}
+
+	return diags
+}
+
+// deleteFiles deletes the given files from the instance.
+func (r InstanceDirectoryResource) deleteFiles(ctx context.Context, server lxd.InstanceServer, m InstanceDirectoryModel, files []string) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	instanceName := m.Instance.ValueString()
+	targetDir := m.TargetPath.ValueString()
+
+	err := common.InstanceDirectoryDelete(ctx, server, instanceName, targetDir, files)
+	if err != nil {
+		diags.AddError(fmt.Sprintf("Failed to delete files from directory %q on instance %q", targetDir, instanceName), err.Error())
+	}
+
+	return diags
+}
@@ -0,0 +1,159 @@
+package instance_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/acctest"
+)
+
+func TestAccInstanceDirectory_basic(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+	sourceDir := t.TempDir()
+
+	writeFile := func(name string, content string) {
+		p := filepath.Join(sourceDir, name)
+		err := os.MkdirAll(filepath.Dir(p), 0755)
+		if err == nil {
+			err = os.WriteFile(p, []byte(content), 0644)
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile("app.conf", "key=value\n")
+	writeFile("conf.d/extra.conf", "extra=true\n")
+	writeFile("notes.tmp", "ignored\n")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceDirectory_basic(instanceName, sourceDir),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance_directory.dir1", "instance", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance_directory.dir1", "target_path", "/etc/app"),
+					resource.TestCheckResourceAttr("lxd_instance_directory.dir1", "directory_mode", "0755"),
+					resource.TestCheckResourceAttr("lxd_instance_directory.dir1", "delete_removed", "true"),
+					resource.TestCheckResourceAttr("lxd_instance_directory.dir1", "files.%", "2"),
+					resource.TestCheckResourceAttrSet("lxd_instance_directory.dir1", "files.app.conf"),
+					resource.TestCheckResourceAttrSet("lxd_instance_directory.dir1", "files.conf.d/extra.conf"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.check", "stdout", "extra=true\nkey=value\n"),
+				),
+			},
+			{
+				// Modify one file, add another one, and remove the third one.
+				PreConfig: func() {
+					writeFile("app.conf", "key=updated\n")
+					writeFile("conf.d/new.conf", "new=true\n")
+					_ = os.Remove(filepath.Join(sourceDir, "conf.d", "extra.conf"))
+				},
+				Config: acctest.Provider() + testAccInstanceDirectory_basic(instanceName, sourceDir),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_directory.dir1", "files.%", "2"),
+					resource.TestCheckResourceAttrSet("lxd_instance_directory.dir1", "files.app.conf"),
+					resource.TestCheckResourceAttrSet("lxd_instance_directory.dir1", "files.conf.d/new.conf"),
+					resource.TestCheckNoResourceAttr("lxd_instance_directory.dir1", "files.conf.d/extra.conf"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.check", "stdout", "new=true\nkey=updated\n"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceDirectory_drift(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+	sourceDir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(sourceDir, "app.conf"), []byte("key=value\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceDirectory_drift(instanceName, sourceDir, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_directory.dir1", "files.%", "1"),
+				),
+			},
+			{
+				// Modify the file within the instance.
+				Config:             acctest.Provider() + testAccInstanceDirectory_drift(instanceName, sourceDir, true),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				// Ensure the file is uploaded again.
+				Config: acctest.Provider() + testAccInstanceDirectory_drift(instanceName, sourceDir, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_directory.dir1", "files.%", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccInstanceDirectory_basic(instanceName string, sourceDir string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+}
+
+resource "lxd_instance_directory" "dir1" {
+  instance    = lxd_instance.instance1.name
+  source_path = "%s"
+  target_path = "/etc/app"
+  include     = ["*.conf"]
+  exclude     = ["*.tmp"]
+  file_mode   = "0640"
+}
+
+resource "lxd_instance_exec" "check" {
+  instance      = lxd_instance.instance1.name
+  command       = ["/bin/sh", "-c", "cat /etc/app/conf.d/*.conf /etc/app/app.conf"]
+  record_output = true
+
+  triggers = lxd_instance_directory.dir1.files
+}
+	`, instanceName, acctest.TestImage, sourceDir)
+}
+
+func testAccInstanceDirectory_drift(instanceName string, sourceDir string, modify bool) string {
+	config := fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+}
+
+resource "lxd_instance_directory" "dir1" {
+  instance    = lxd_instance.instance1.name
+  source_path = "%s"
+  target_path = "/etc/app"
+}
+	`, instanceName, acctest.TestImage, sourceDir)
+
+	if modify {
+		config += `
+resource "lxd_instance_exec" "modify" {
+  instance = lxd_instance.instance1.name
+  command  = ["/bin/sh", "-c", "echo modified > /etc/app/app.conf"]
+
+  triggers = lxd_instance_directory.dir1.files
+}
+	`
+	}
+
+	return config
+}
@@ -0,0 +1,508 @@
+package instance
+
+import (
+	"context"
+	"fmt"
+
+	lxd "github.com/canonical/lxd/client"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/common"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
+	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
+)
+
+// InstanceExecModel represents a command executed on an LXD instance.
+//
+// This model should embed common.ExecModel, but terraform-framework does
+// not yet support unmarshaling of embedded structs.
+// https://github.com/hashicorp/terraform-plugin-framework/issues/242
+type InstanceExecModel struct {
+	Instance  types.String `tfsdk:"instance"`
+	Stdin     types.String `tfsdk:"stdin"`
+	Triggers  types.Map    `tfsdk:"triggers"`
+	OnDestroy types.List   `tfsdk:"on_destroy"`
+	Project   types.String `tfsdk:"project"`
+	Remote    types.String `tfsdk:"remote"`
+
+	// common.ExecModel
+	Command       types.List   `tfsdk:"command"`
+	Environment   types.Map    `tfsdk:"environment"`
+	WorkingDir    types.String `tfsdk:"working_dir"`
+	Trigger       types.String `tfsdk:"trigger"`
+	Enabled       types.Bool   `tfsdk:"enabled"`
+	RecordOutput  types.Bool   `tfsdk:"record_output"`
+	FailOnError   types.Bool   `tfsdk:"fail_on_error"`
+	UserID        types.Int64  `tfsdk:"uid"`
+	GroupID       types.Int64  `tfsdk:"gid"`
+	Timeout       types.String `tfsdk:"timeout"`
+	Retries       types.Int64  `tfsdk:"retries"`
+	RetryInterval types.String `tfsdk:"retry_interval"`
+	StreamOutput  types.Bool   `tfsdk:"stream_output"`
+
+	// Computed.
+	ExitCode types.Int64  `tfsdk:"exit_code"`
+	Output   types.String `tfsdk:"stdout"`
+	Error    types.String `tfsdk:"stderr"`
+	RunCount types.Int64  `tfsdk:"run_count"`
+}
+
+// InstanceExecResource represent LXD instance exec resource.
+type InstanceExecResource struct {
+	provider *provider_config.LxdProviderConfig
+}
+
+// NewInstanceExecResource returns a new instance exec resource.
+func NewInstanceExecResource() resource.Resource {
+	return &InstanceExecResource{}
+}
+
+func (r InstanceExecResource) Metadata(_ context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_instance_exec"
+}
+
+func (r InstanceExecResource) Schema(_ context.Context, _ resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"instance": schema.StringAttribute{
+				Required: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			"command": schema.ListAttribute{
+				Description: "Command to run within the instance",
+				Required:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+
+			"environment": schema.MapAttribute{
+				Description: "Map of additional environment variables",
+				Optional:    true,
+				Computed:    true,
+				ElementType: types.StringType,
+				Default:     mapdefault.StaticValue(types.MapValueMust(types.StringType, map[string]attr.Value{})),
+				Validators: []validator.Map{
+					mapvalidator.KeysAre(stringvalidator.LengthAtLeast(1)),
+					mapvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+
+			"working_dir": schema.StringAttribute{
+				Description: "The directory in which the command should run",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(""),
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"stdin": schema.StringAttribute{
+				Description: "Input passed to the command's standard input",
+				Optional:    true,
+			},
+
+			"trigger": schema.StringAttribute{
+				Description: "Determines when the command should be executed",
+				Optional:    true,
+				Computed:    true,
+				Default:     stringdefault.StaticString(common.ON_CHANGE.String()),
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						common.ON_CHANGE.String(),
+						common.ONCE.String(),
+					),
+				},
+			},
+
+			"triggers": schema.MapAttribute{
+				Description: "Map of arbitrary values that re-run the command when changed",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+
+			"enabled": schema.BoolAttribute{
+				Description: "Whether the command should be executed",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(true),
+			},
+
+			"record_output": schema.BoolAttribute{
+				Description: "Whether to record command's output (stdout and stderr)",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+
+			"fail_on_error": schema.BoolAttribute{
+				Description: "Whether to fail on command error",
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+			},
+
+			"uid": schema.Int64Attribute{
+				Description: "The user ID for running command",
+				Optional:    true,
+			},
+
+			"gid": schema.Int64Attribute{
+				Description: "The group ID for running command",
+				Optional:    true,
+			},
+
+			"timeout": schema.StringAttribute{
+				Description: "Time to wait for the command to complete",
+				Optional:    true,
+				Validators: []validator.String{
+					durationValidator{},
+				},
+			},
+
+			"retries": schema.Int64Attribute{
+				Description: "Number of times a failed command is retried",
+				Optional:    true,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+
+			"retry_interval": schema.StringAttribute{
+				Description: "Time to wait between command retries",
+				Optional:    true,
+				Validators: []validator.String{
+					durationValidator{},
+				},
+			},
+
+			"stream_output": schema.BoolAttribute{
+				Description: "Whether to stream command's output to the provider log",
+				Optional:    true,
+			},
+
+			"on_destroy": schema.ListAttribute{
+				Description: "Command to run within the instance when the resource is destroyed",
+				Optional:    true,
+				ElementType: types.StringType,
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+				},
+			},
+
+			"project": schema.StringAttribute{
+				Optional: true,
+				Computed: true,
+				Default:  stringdefault.StaticString(provider_config.DefaultProject),
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+
+			"remote": schema.StringAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+
+			// Computed.
+
+			"exit_code": schema.Int64Attribute{
+				Description: "Exit code of the command",
+				Computed:    true,
+			},
+
+			"stdout": schema.StringAttribute{
+				Description: "Command standard output (if recorded)",
+				Computed:    true,
+			},
+
+			"stderr": schema.StringAttribute{
+				Description: "Command standard error (if recorded)",
+				Computed:    true,
+			},
+
+			"run_count": schema.Int64Attribute{
+				Description: "Number of times the command was executed",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func (r *InstanceExecResource) Configure(_ context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	data := req.ProviderData
+	if data == nil {
+		return
+	}
+
+	provider, ok := data.(*provider_config.LxdProviderConfig)
+	if !ok {
+		resp.Diagnostics.Append(errors.NewProviderDataTypeError(req.ProviderData))
+		return
+	}
+
+	r.provider = provider
+}
+
+func (r InstanceExecResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan InstanceExecModel
+
+	// Fetch resource model from Terraform plan.
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := plan.Remote.ValueString()
+	project := plan.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	// Exit code -1 indicates the command was not executed.
+	plan.ExitCode = types.Int64Value(-1)
+	plan.Output = types.StringValue("")
+	plan.Error = types.StringValue("")
+	plan.RunCount = types.Int64Value(0)
+
+	exec := r.toExecModel(plan)
+	if exec.IsTriggered(true) {
+		diags := r.execute(ctx, server, &plan)
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+	}
+
+	// Update Terraform state.
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r InstanceExecResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state InstanceExecModel
+
+	// Fetch resource model from Terraform state.
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := state.Remote.ValueString()
+	project := state.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	// Ensure instance exists.
+	instanceName := state.Instance.ValueString()
+	_, _, err = server.GetInstance(instanceName)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			// If instance is not found, remove the command from the
+			// state to ensure it is executed again on next apply.
+			resp.State.RemoveResource(ctx)
+			return
+		}
+
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed retrieve instance %q", instanceName), err.Error())
+		return
+	}
+}
+
+// Update re-runs the command if it is triggered by the change. Otherwise,
+// the computed values from the previous execution are retained.
+func (r InstanceExecResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan InstanceExecModel
+	var state InstanceExecModel
+
+	// Fetch resource model from Terraform plan and state.
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := plan.Remote.ValueString()
+	project := plan.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	// Retain values from the previous execution.
+	plan.ExitCode = state.ExitCode
+	plan.Output = state.Output
+	plan.Error = state.Error
+	plan.RunCount = state.RunCount
+
+	if isExecRerun(r.toExecModel(plan), plan, state) {
+		diags := r.execute(ctx, server, &plan)
+		if diags.HasError() {
+			resp.Diagnostics.Append(diags...)
+			return
+		}
+	}
+
+	// Update Terraform state.
+	diags := resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// Delete runs the on_destroy command (if set) within the instance. If the
+// instance does not exist or is not running, the command is skipped. The
+// command runs with the environment, working directory, user, group, and
+// timeout of the main command, but without its standard input and retries.
+func (r InstanceExecResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state InstanceExecModel
+
+	// Fetch resource model from Terraform state.
+	diags := req.State.Get(ctx, &state)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.OnDestroy.IsNull() || !state.Enabled.ValueBool() {
+		return
+	}
+
+	remote := state.Remote.ValueString()
+	project := state.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	instanceName := state.Instance.ValueString()
+	instanceState, _, err := server.GetInstanceState(instanceName)
+	if err != nil {
+		if errors.IsNotFoundError(err) {
+			return
+		}
+
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve state of instance %q", instanceName), err.Error())
+		return
+	}
+
+	if !isInstanceRunning(*instanceState) {
+		resp.Diagnostics.AddWarning(
+			fmt.Sprintf("Skipped on_destroy command on instance %q", instanceName),
+			"Command cannot be executed, because the instance is not running.",
+		)
+		return
+	}
+
+	exec := r.toExecModel(state)
+	exec.Command = state.OnDestroy
+	exec.Stdin = types.StringNull()
+	exec.Retries = types.Int64Null()
+	exec.RecordOutput = types.BoolValue(false)
+
+	resp.Diagnostics.Append(exec.Execute(ctx, server, instanceName)...)
+}
+
+// isExecRerun determines whether the command is executed again on update.
+// A change of triggers re-runs an enabled command regardless of its trigger.
+// Otherwise, a command with trigger "on_change" is re-run only when an input
+// of the command changes. Attributes that do not affect the command, such as
+// output handling or the on_destroy command, do not re-run it.
+func isExecRerun(exec common.ExecModel, plan InstanceExecModel, state InstanceExecModel) bool {
+	if !plan.Enabled.ValueBool() {
+		return false
+	}
+
+	if !plan.Triggers.Equal(state.Triggers) {
+		return true
+	}
+
+	if !exec.IsTriggered(false) {
+		return false
+	}
+
+	// Command with trigger "once" that was not executed yet.
+	if plan.Trigger.ValueString() == common.ONCE.String() {
+		return true
+	}
+
+	return !plan.Enabled.Equal(state.Enabled) ||
+		!plan.Command.Equal(state.Command) ||
+		!plan.Environment.Equal(state.Environment) ||
+		!plan.WorkingDir.Equal(state.WorkingDir) ||
+		!plan.Stdin.Equal(state.Stdin) ||
+		!plan.UserID.Equal(state.UserID) ||
+		!plan.GroupID.Equal(state.GroupID)
+}
+
+// execute runs the command and updates the model's computed values.
+func (r InstanceExecResource) execute(ctx context.Context, server lxd.InstanceServer, m *InstanceExecModel) diag.Diagnostics {
+	instanceName := m.Instance.ValueString()
+
+	exec := r.toExecModel(*m)
+	diags := exec.Execute(ctx, server, instanceName)
+	if diags.HasError() {
+		return diags
+	}
+
+	m.ExitCode = exec.ExitCode
+	m.Output = exec.Output
+	m.Error = exec.Error
+	m.RunCount = exec.RunCount
+
+	return diags
+}
+
+// toExecModel converts the resource model into common.ExecModel.
+func (r InstanceExecResource) toExecModel(m InstanceExecModel) common.ExecModel {
+	return common.ExecModel{
+		Command:       m.Command,
+		Environment:   m.Environment,
+		WorkingDir:    m.WorkingDir,
+		Trigger:       m.Trigger,
+		Enabled:       m.Enabled,
+		RecordOutput:  m.RecordOutput,
+		FailOnError:   m.FailOnError,
+		UserID:        m.UserID,
+		GroupID:       m.GroupID,
+		Stdin:         m.Stdin,
+		Timeout:       m.Timeout,
+		Retries:       m.Retries,
+		RetryInterval: m.RetryInterval,
+		StreamOutput:  m.StreamOutput,
+		ExitCode:      m.ExitCode,
+		Output:        m.Output,
+		Error:         m.Error,
+		RunCount:      m.RunCount,
+	}
+}
@@ -0,0 +1,247 @@
+package instance_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/acctest"
+)
+
+func TestAccInstanceExec_basic(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceExec_basic(instanceName, "hello"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "instance", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "exit_code", "0"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "stdout", "hello\n"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "stderr", ""),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "run_count", "1"),
+				),
+			},
+			{
+				// Ensure the command is not executed again without changes.
+				Config: acctest.Provider() + testAccInstanceExec_basic(instanceName, "hello"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "stdout", "hello\n"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "run_count", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceExec_user(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceExec_user(instanceName, 1000, 2000),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "uid", "1000"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "gid", "2000"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "exit_code", "0"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "stdout", "1000:2000\n"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceExec_triggers(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceExec_triggers(instanceName, "on_change", "v1", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "triggers.version", "v1"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "run_count", "1"),
+				),
+			},
+			{
+				// Changed trigger re-runs the command.
+				Config: acctest.Provider() + testAccInstanceExec_triggers(instanceName, "on_change", "v2", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "triggers.version", "v2"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "run_count", "2"),
+				),
+			},
+			{
+				// Attributes that do not affect the command do not re-run it.
+				Config: acctest.Provider() + testAccInstanceExec_triggers(instanceName, "on_change", "v2", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "record_output", "true"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "run_count", "2"),
+				),
+			},
+			{
+				// Command with trigger "once" is not executed again.
+				Config: acctest.Provider() + testAccInstanceExec_triggers(instanceName, "once", "v2", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "trigger", "once"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "run_count", "2"),
+				),
+			},
+			{
+				// Changed trigger re-runs the command regardless of its trigger.
+				Config: acctest.Provider() + testAccInstanceExec_triggers(instanceName, "once", "v3", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "triggers.version", "v3"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "run_count", "3"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceExec_stdin(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceExec_stdin(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "stdin", "Hello, World!\n"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "exit_code", "0"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "stdout", "Hello, World!\n"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceExec_onDestroy(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceExec_onDestroy(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "on_destroy.#", "2"),
+					resource.TestCheckResourceAttr("lxd_instance_exec.exec1", "run_count", "1"),
+				),
+			},
+			{
+				// Destroy exec resource, which removes the created file.
+				Config: acctest.Provider() + testAccInstanceExec_instance(instanceName),
+			},
+			{
+				// Ensure the file was removed.
+				Config: acctest.Provider() + testAccInstanceExec_onDestroyCheck(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_exec.check", "exit_code", "1"),
+				),
+			},
+		},
+	})
+}
+
+func testAccInstanceExec_instance(instanceName string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+}
+	`, instanceName, acctest.TestImage)
+}
+
+func testAccInstanceExec_basic(instanceName string, message string) string {
+	exec := fmt.Sprintf(`
+resource "lxd_instance_exec" "exec1" {
+  instance      = lxd_instance.instance1.name
+  command       = ["echo", "%s"]
+  record_output = true
+}
+	`, message)
+
+	return testAccInstanceExec_instance(instanceName) + exec
+}
+
+func testAccInstanceExec_user(instanceName string, uid int, gid int) string {
+	exec := fmt.Sprintf(`
+resource "lxd_instance_exec" "exec1" {
+  instance      = lxd_instance.instance1.name
+  command       = ["/bin/sh", "-c", "echo $(id -u):$(id -g)"]
+  uid           = %d
+  gid           = %d
+  record_output = true
+}
+	`, uid, gid)
+
+	return testAccInstanceExec_instance(instanceName) + exec
+}
+
+func testAccInstanceExec_triggers(instanceName string, trigger string, version string, recordOutput bool) string {
+	exec := fmt.Sprintf(`
+resource "lxd_instance_exec" "exec1" {
+  instance      = lxd_instance.instance1.name
+  command       = ["touch", "/tmp/trigger"]
+  trigger       = "%s"
+  record_output = %t
+
+  triggers = {
+    version = "%s"
+  }
+}
+	`, trigger, recordOutput, version)
+
+	return testAccInstanceExec_instance(instanceName) + exec
+}
+
+func testAccInstanceExec_stdin(instanceName string) string {
+	exec := `
+resource "lxd_instance_exec" "exec1" {
+  instance      = lxd_instance.instance1.name
+  command       = ["cat"]
+  stdin         = "Hello, World!\n"
+  record_output = true
+}
+	`
+
+	return testAccInstanceExec_instance(instanceName) + exec
+}
+
+func testAccInstanceExec_onDestroy(instanceName string) string {
+	exec := `
+resource "lxd_instance_exec" "exec1" {
+  instance   = lxd_instance.instance1.name
+  command    = ["/bin/sh", "-c", "cat > /tmp/created"]
+  stdin      = "created"
+  on_destroy = ["rm", "/tmp/created"]
+}
+	`
+
+	return testAccInstanceExec_instance(instanceName) + exec
+}
+
+func testAccInstanceExec_onDestroyCheck(instanceName string) string {
+	check := `
+resource "lxd_instance_exec" "check" {
+  instance = lxd_instance.instance1.name
+  command  = ["test", "-f", "/tmp/created"]
+}
+	`
+
+	return testAccInstanceExec_instance(instanceName) + check
+}
@@ -3,9 +3,11 @@ package instance
 import (
 	"context"
 	"fmt"
+	"io"
 	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/resource"
 	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
@@ -34,14 +36,20 @@ type InstanceFileModel struct {
 	Remote     types.String `tfsdk:"remote"`
 
 	// common.InstanceFileModel
-	Content    types.String `tfsdk:"content"`
-	SourcePath types.String `tfsdk:"source_path"`
-	TargetPath types.String `tfsdk:"target_path"`
-	UserID     types.Int64  `tfsdk:"uid"`
-	GroupID    types.Int64  `tfsdk:"gid"`
-	Mode       types.String `tfsdk:"mode"`
-	CreateDirs types.Bool   `tfsdk:"create_directories"`
-	Append     types.Bool   `tfsdk:"append"`
+	Type          types.String `tfsdk:"type"`
+	Content       types.String `tfsdk:"content"`
+	SourcePath    types.String `tfsdk:"source_path"`
+	SymlinkTarget types.String `tfsdk:"symlink_target"`
+	TargetPath    types.String `tfsdk:"target_path"`
+	UserID        types.Int64  `tfsdk:"uid"`
+	GroupID       types.Int64  `tfsdk:"gid"`
+	Mode          types.String `tfsdk:"mode"`
+	CreateDirs    types.Bool   `tfsdk:"create_directories"`
+	Append        types.Bool   `tfsdk:"append"`
+
+	// Computed.
+	SHA256       types.String `tfsdk:"sha256"`
+	SourceSHA256 types.String `tfsdk:"source_sha256"`
 }
 
 // InstanceFileResource represent LXD instance file resource.
@@ -91,6 +99,20 @@ func (r InstanceFileResource) Schema(_ context.Context, _ resource.SchemaRequest
 				Optional: true,
 			},
 
+			"type": schema.StringAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
+				Validators: []validator.String{
+					stringvalidator.OneOf(
+						common.FileTypeFile,
+						common.FileTypeDirectory,
+						common.FileTypeSymlink,
+					),
+				},
+			},
+
 			"content": schema.StringAttribute{
 				Optional: true,
 				PlanModifiers: []planmodifier.String{
@@ -104,12 +126,14 @@ func (r InstanceFileResource) Schema(_ context.Context, _ resource.SchemaRequest
 					stringplanmodifier.RequiresReplace(),
 				},
 				Validators: []validator.String{
-					// Specify all attributes at one field to
-					// produce only one meaningful error.
-					stringvalidator.ExactlyOneOf(
-						path.MatchRoot("source_path"),
-						path.MatchRoot("content"),
-					),
+					stringvalidator.ConflictsWith(path.MatchRoot("content")),
+				},
+			},
+
+			"symlink_target": schema.StringAttribute{
+				Optional: true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
 				},
 			},
 
@@ -160,6 +184,18 @@ func (r InstanceFileResource) Schema(_ context.Context, _ resource.SchemaRequest
 					boolplanmodifier.RequiresReplace(),
 				},
 			},
+
+			// Computed.
+
+			"sha256": schema.StringAttribute{
+				Description: "SHA-256 checksum of the file content within the instance",
+				Computed:    true,
+			},
+
+			"source_sha256": schema.StringAttribute{
+				Description: "SHA-256 checksum of the source file",
+				Computed:    true,
+			},
 		},
 	}
 }
@@ -179,6 +215,77 @@ func (r *InstanceFileResource) Configure(_ context.Context, req resource.Configu
 	r.provider = provider
 }
 
+func (r InstanceFileResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config InstanceFileModel
+
+	diags := req.Config.Get(ctx, &config)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	err := config.toCommonModel().Validate()
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid Configuration", err.Error())
+	}
+}
+
+// ModifyPlan computes the checksum of the file content. If the checksum
+// differs from the checksum of the file within the instance, either because
+// the source file has changed or the file was modified within the instance,
+// the file is uploaded again.
+func (r InstanceFileResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan InstanceFileModel
+	var state *InstanceFileModel
+
+	diags := req.Plan.Get(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
+
+	if !req.State.Raw.IsNull() {
+		diags = req.State.Get(ctx, &state)
+		resp.Diagnostics.Append(diags...)
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	plan.SHA256, plan.SourceSHA256, diags = fileChecksums(plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state != nil {
+		if plan.Append.ValueBool() {
+			// Content is appended to the file, therefore the file
+			// is recreated when the source file changes.
+			if !state.SourceSHA256.IsNull() && !plan.SourceSHA256.Equal(state.SourceSHA256) {
+				resp.RequiresReplace.Append(path.Root("source_sha256"))
+			}
+		} else if state.Content.IsNull() && state.SourcePath.IsNull() {
+			// Imported file has neither content nor source path in
+			// the state. Avoid replacing the file, as it is uploaded
+			// only if checksums do not match. Parent directories of
+			// an imported file already exist.
+			requiresReplace := make(path.Paths, 0, len(resp.RequiresReplace))
+			for _, p := range resp.RequiresReplace {
+				if !p.Equal(path.Root("content")) && !p.Equal(path.Root("source_path")) && !p.Equal(path.Root("create_directories")) {
+					requiresReplace = append(requiresReplace, p)
+				}
+			}
+
+			resp.RequiresReplace = requiresReplace
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, &plan)...)
+}
+
 func (r InstanceFileResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
 	var plan InstanceFileModel
 
@@ -205,16 +312,7 @@ func (r InstanceFileResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
-	file := common.InstanceFileModel{
-		Content:    plan.Content,
-		SourcePath: plan.SourcePath,
-		TargetPath: plan.TargetPath,
-		UserID:     plan.UserID,
-		GroupID:    plan.GroupID,
-		Mode:       plan.Mode,
-		CreateDirs: plan.CreateDirs,
-		Append:     plan.Append,
-	}
+	file := plan.toCommonModel()
 
 	// Upload file.
 	targetPath := plan.TargetPath.ValueString()
@@ -224,6 +322,9 @@ func (r InstanceFileResource) Create(ctx context.Context, req resource.CreateReq
 		return
 	}
 
+	plan.SHA256, plan.SourceSHA256, diags = fileChecksums(plan)
+	resp.Diagnostics.Append(diags...)
+
 	fileID := createFileResourceID(remote, instanceName, targetPath)
 	plan.ResourceID = types.StringValue(fileID)
 
@@ -266,7 +367,7 @@ func (r InstanceFileResource) Read(ctx context.Context, req resource.ReadRequest
 	}
 
 	// Fetch an existing file.
-	_, file, err := server.GetInstanceFile(instanceName, targetPath)
+	content, file, err := server.GetInstanceFile(instanceName, targetPath)
 	if err != nil {
 		if errors.IsNotFoundError(err) {
 			// If file is not found, remove it from the Terraform state
@@ -279,18 +380,111 @@ func (r InstanceFileResource) Read(ctx context.Context, req resource.ReadRequest
 		return
 	}
 
+	// Content of the symlink is its target. For regular files,
+	// only the checksum of the content is needed.
+	var sum string
+	var symlinkTarget []byte
+	if content != nil {
+		if file.Type == common.FileTypeSymlink {
+			symlinkTarget, err = io.ReadAll(content)
+		} else {
+			sum, err = common.ReaderSHA256(content)
+		}
+
+		content.Close()
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to read file %q from instance %q", targetPath, instanceName), err.Error())
+			return
+		}
+	}
+
 	state.Instance = types.StringValue(instanceName)
 	state.TargetPath = types.StringValue(targetPath)
 	state.UserID = types.Int64Value(file.UID)
 	state.GroupID = types.Int64Value(file.GID)
-	state.Mode = types.StringValue(fmt.Sprintf("%04o", file.Mode))
+
+	// Set the actual file type if it differs from the configured one
+	// (null type represents a regular file).
+	if file.Type != state.toCommonModel().FileType() {
+		state.Type = types.StringValue(file.Type)
+	}
+
+	switch file.Type {
+	case common.FileTypeDirectory:
+		state.Mode = types.StringValue(fmt.Sprintf("%04o", file.Mode))
+		state.SHA256 = types.StringNull()
+	case common.FileTypeSymlink:
+		// Symlink permissions are not meaningful, therefore the
+		// configured mode is retained.
+		state.SymlinkTarget = types.StringValue(string(symlinkTarget))
+		state.SHA256 = types.StringNull()
+	default:
+		state.Mode = types.StringValue(fmt.Sprintf("%04o", file.Mode))
+
+		// Checksum of a file with appended content cannot be compared
+		// with the checksum of the content.
+		if state.Append.ValueBool() {
+			state.SHA256 = types.StringNull()
+		} else {
+			state.SHA256 = types.StringValue(sum)
+
+			// Populate the source checksum if missing (e.g. the state was
+			// created by an older version of the provider). Source file was
+			// uploaded, therefore its checksum matches the file's checksum.
+			if !state.SourcePath.IsNull() && state.SourceSHA256.IsNull() {
+				state.SourceSHA256 = state.SHA256
+			}
+		}
+	}
 
 	// Update Terraform state.
 	diags = resp.State.Set(ctx, &state)
 	resp.Diagnostics.Append(diags...)
 }
 
+// Update uploads the file again if the checksum of the file within
+// the instance does not match the checksum of the file content.
 func (r InstanceFileResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan InstanceFileModel
+	var state InstanceFileModel
+
+	// Fetch resource model from Terraform plan and state.
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := plan.Remote.ValueString()
+	project := plan.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	var diags diag.Diagnostics
+	plan.SHA256, plan.SourceSHA256, diags = fileChecksums(plan)
+	resp.Diagnostics.Append(diags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !plan.Append.ValueBool() && !plan.SHA256.Equal(state.SHA256) {
+		file := plan.toCommonModel()
+
+		instanceName := plan.Instance.ValueString()
+		targetPath := plan.TargetPath.ValueString()
+		err = common.InstanceFileUpload(server, instanceName, file)
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to update file %q on instance %q", targetPath, instanceName), err.Error())
+			return
+		}
+	}
+
+	// Update Terraform state.
+	diags = resp.State.Set(ctx, &plan)
+	resp.Diagnostics.Append(diags...)
 }
 
 func (r InstanceFileResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -324,13 +518,66 @@ func (r InstanceFileResource) Delete(ctx context.Context, req resource.DeleteReq
 	}
 
 	// Delete file.
-	err = common.InstanceFileDelete(server, instanceName, state.TargetPath.ValueString())
+	err = common.InstanceFileDelete(ctx, server, instanceName, state.TargetPath.ValueString())
 	if err != nil && !errors.IsNotFoundError(err) {
 		resp.Diagnostics.AddError(fmt.Sprintf("Failed to delete file %q from instance %q", targetFile, instanceName), err.Error())
 		return
 	}
 }
 
+// ImportState imports an existing file. Import ID has the following format:
+// "[remote:][project/]instance:/path/to/file".
+func (r InstanceFileResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	meta := common.ImportMetadata{
+		ResourceName:   "instance_file",
+		RequiredFields: []string{"instance"},
+	}
+
+	// Target path is an absolute path, which contains slashes. Therefore,
+	// it is separated from the rest of the import ID using colon.
+	id, targetPath, found := strings.Cut(req.ID, ":/")
+	if !found {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Invalid import ID: %q", req.ID),
+			"Import ID does not contain an absolute target file path.\n\nValid import format:\nimport lxd_instance_file.<resource> [<remote>:][<project>/]<instance>:<target_path>",
+		)
+		return
+	}
+
+	fields, diag := meta.ParseImportID(id)
+	if diag != nil {
+		resp.Diagnostics.Append(diag)
+		return
+	}
+
+	if fields["project"] == "" {
+		fields["project"] = provider_config.DefaultProject
+	}
+
+	fields["target_path"] = "/" + targetPath
+	fields["resource_id"] = createFileResourceID(fields["remote"], fields["instance"], fields["target_path"])
+
+	for k, v := range fields {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(k), v)...)
+	}
+}
+
+// toCommonModel converts the resource model into common.InstanceFileModel.
+func (m InstanceFileModel) toCommonModel() common.InstanceFileModel {
+	return common.InstanceFileModel{
+		Type:          m.Type,
+		Content:       m.Content,
+		SourcePath:    m.SourcePath,
+		SymlinkTarget: m.SymlinkTarget,
+		TargetPath:    m.TargetPath,
+		UserID:        m.UserID,
+		GroupID:       m.GroupID,
+		Mode:          m.Mode,
+		CreateDirs:    m.CreateDirs,
+		Append:        m.Append,
+	}
+}
+
 // createFileResourceID creates new file ID by concatenating remote,
 // instnaceName, and targetPath using colon.
 func createFileResourceID(remote string, instanceName string, targetPath string) string {
@@ -347,3 +594,42 @@ func splitFileResourceID(id string) (remote string, instanceName string, targetP
 
 	return pieces[0], pieces[1], pieces[2]
 }
+
+// fileChecksums returns the expected checksum of the file content within
+// the instance, and the checksum of the source file (if source path is set).
+// Unknown values are returned if the content or source path is not yet known.
+func fileChecksums(m InstanceFileModel) (types.String, types.String, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	if m.Type.IsUnknown() || m.Content.IsUnknown() || m.SourcePath.IsUnknown() {
+		return types.StringUnknown(), types.StringUnknown(), nil
+	}
+
+	// Checksums are tracked only for regular files.
+	if m.toCommonModel().FileType() != common.FileTypeFile {
+		return types.StringNull(), types.StringNull(), nil
+	}
+
+	contentSHA256 := types.StringNull()
+	sourceSHA256 := types.StringNull()
+
+	if !m.SourcePath.IsNull() {
+		sum, err := common.LocalFileSHA256(m.SourcePath.ValueString())
+		if err != nil {
+			diags.AddAttributeError(path.Root("source_path"), "Failed to compute source file checksum", err.Error())
+			return contentSHA256, sourceSHA256, diags
+		}
+
+		contentSHA256 = types.StringValue(sum)
+		sourceSHA256 = types.StringValue(sum)
+	} else {
+		contentSHA256 = types.StringValue(common.ContentSHA256(m.Content.ValueString()))
+	}
+
+	// Checksum of a file with appended content is not tracked.
+	if m.Append.ValueBool() {
+		contentSHA256 = types.StringNull()
+	}
+
+	return contentSHA256, sourceSHA256, diags
+}
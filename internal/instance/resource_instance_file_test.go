@@ -2,10 +2,15 @@ package instance_test
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/terraform-lxd/terraform-provider-lxd/internal/acctest"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/common"
 )
 
 func TestAccInstanceFile_basic(t *testing.T) {
@@ -83,6 +88,200 @@ func TestAccInstanceFile_project(t *testing.T) {
 	})
 }
 
+func TestAccInstanceFile_contentDrift(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceFile_content(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					// SHA-256 checksum of "Hello, World!\n".
+					resource.TestCheckResourceAttr("lxd_instance_file.file1", "sha256", "c98c24b677eff44860afea6f493bbaec5bb1c4cbb209c6fc2bbb47f66ff2ad31"),
+					resource.TestCheckNoResourceAttr("lxd_instance_file.file1", "source_sha256"),
+				),
+			},
+			{
+				// Modify the file within the instance.
+				Config:             acctest.Provider() + testAccInstanceFile_contentModified(instanceName),
+				ExpectNonEmptyPlan: true,
+			},
+			{
+				// Ensure the file is uploaded again.
+				Config: acctest.Provider() + testAccInstanceFile_content(instanceName),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("lxd_instance_file.file1", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_file.file1", "sha256", "c98c24b677eff44860afea6f493bbaec5bb1c4cbb209c6fc2bbb47f66ff2ad31"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceFile_sourceChange(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+	sourcePath := filepath.Join(t.TempDir(), "source.txt")
+
+	writeSource := func(content string) {
+		err := os.WriteFile(sourcePath, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeSource("v1\n")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceFile_source(instanceName, sourcePath),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_file.file1", "source_path", sourcePath),
+					resource.TestCheckResourceAttr("lxd_instance_file.file1", "source_sha256", common.ContentSHA256("v1\n")),
+					resource.TestCheckResourceAttr("lxd_instance_file.file1", "sha256", common.ContentSHA256("v1\n")),
+				),
+			},
+			{
+				// Modify the source file. The file should be updated in place.
+				PreConfig: func() { writeSource("v2\n") },
+				Config:    acctest.Provider() + testAccInstanceFile_source(instanceName, sourcePath),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("lxd_instance_file.file1", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_file.file1", "source_sha256", common.ContentSHA256("v2\n")),
+					resource.TestCheckResourceAttr("lxd_instance_file.file1", "sha256", common.ContentSHA256("v2\n")),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceFile_importBasic(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+	resourceName := "lxd_instance_file.file1"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceFile_content(instanceName),
+			},
+			{
+				Config:                               acctest.Provider() + testAccInstanceFile_content(instanceName),
+				ResourceName:                         resourceName,
+				ImportStateId:                        fmt.Sprintf("%s:/foo/bar.txt", instanceName),
+				ImportStateVerifyIdentifierAttribute: "resource_id",
+				ImportStateVerifyIgnore:              []string{"content", "create_directories"},
+				ImportState:                          true,
+				ImportStateVerify:                    true,
+			},
+			{
+				Config:             acctest.Provider() + testAccInstanceFile_content(instanceName),
+				ResourceName:       resourceName,
+				ImportStateId:      fmt.Sprintf("%s:/foo/bar.txt", instanceName),
+				ImportState:        true,
+				ImportStatePersist: true,
+			},
+			{
+				// Ensure the imported file is updated in place to record
+				// its content in the state.
+				Config: acctest.Provider() + testAccInstanceFile_content(instanceName),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction(resourceName, plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resourceName, "content", "Hello, World!\n"),
+					resource.TestCheckResourceAttr(resourceName, "create_directories", "true"),
+					resource.TestCheckResourceAttr(resourceName, "sha256", common.ContentSHA256("Hello, World!\n")),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceFile_directory(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceFile_directory(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance_file.dir1", "type", "directory"),
+					resource.TestCheckResourceAttr("lxd_instance_file.dir1", "target_path", "/opt/app"),
+					resource.TestCheckResourceAttr("lxd_instance_file.dir1", "mode", "0750"),
+					resource.TestCheckNoResourceAttr("lxd_instance_file.dir1", "sha256"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.dir1", "type", "directory"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.dir1", "mode", "0750"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceFile_symlink(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceFile_symlink(instanceName, "/etc/hostname"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance_file.link1", "type", "symlink"),
+					resource.TestCheckResourceAttr("lxd_instance_file.link1", "target_path", "/opt/hostname"),
+					resource.TestCheckResourceAttr("lxd_instance_file.link1", "symlink_target", "/etc/hostname"),
+					resource.TestCheckNoResourceAttr("lxd_instance_file.link1", "sha256"),
+				),
+			},
+			{
+				// Changing the symlink target recreates the symlink.
+				Config: acctest.Provider() + testAccInstanceFile_symlink(instanceName, "/etc/hosts"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("lxd_instance_file.link1", plancheck.ResourceActionReplace),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance_file.link1", "symlink_target", "/etc/hosts"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceFile_invalidType(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.Provider() + testAccInstanceFile_invalidType(),
+				ExpectError: regexp.MustCompile(`cannot be used with file type "directory"`),
+			},
+		},
+	})
+}
+
 func testAccInstanceFile_content(name string) string {
 	return fmt.Sprintf(`
 resource "lxd_instance" "instance1" {
@@ -115,6 +314,35 @@ resource "lxd_instance_file" "file1" {
 	`, name, acctest.TestImage)
 }
 
+func testAccInstanceFile_contentModified(name string) string {
+	return testAccInstanceFile_content(name) + `
+resource "lxd_instance_exec" "modify" {
+  instance = lxd_instance.instance1.name
+  command  = ["/bin/sh", "-c", "echo modified > /foo/bar.txt"]
+
+  triggers = {
+    file_id = lxd_instance_file.file1.resource_id
+  }
+}
+	`
+}
+
+func testAccInstanceFile_source(name string, sourcePath string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+}
+
+resource "lxd_instance_file" "file1" {
+  instance           = lxd_instance.instance1.name
+  source_path        = "%s"
+  target_path        = "/foo/bar.txt"
+  create_directories = true
+}
+	`, name, acctest.TestImage, sourcePath)
+}
+
 func testAccInstanceFile_project(project, instance string) string {
 	return fmt.Sprintf(`
 resource "lxd_project" "project1" {
@@ -140,3 +368,52 @@ resource "lxd_instance_file" "file1" {
 }
 	`, project, instance, acctest.TestImage)
 }
+
+func testAccInstanceFile_directory(name string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+}
+
+resource "lxd_instance_file" "dir1" {
+  instance    = lxd_instance.instance1.name
+  type        = "directory"
+  target_path = "/opt/app"
+  mode        = "0750"
+}
+
+data "lxd_instance_file" "dir1" {
+  instance = lxd_instance.instance1.name
+  path     = lxd_instance_file.dir1.target_path
+}
+	`, name, acctest.TestImage)
+}
+
+func testAccInstanceFile_symlink(name string, target string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+}
+
+resource "lxd_instance_file" "link1" {
+  instance           = lxd_instance.instance1.name
+  type               = "symlink"
+  target_path        = "/opt/hostname"
+  symlink_target     = "%s"
+  create_directories = true
+}
+	`, name, acctest.TestImage, target)
+}
+
+func testAccInstanceFile_invalidType() string {
+	return `
+resource "lxd_instance_file" "file1" {
+  instance    = "instance1"
+  type        = "directory"
+  content     = "Hello, World!\n"
+  target_path = "/foo"
+}
+	`
+}
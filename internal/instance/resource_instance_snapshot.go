@@ -22,16 +22,19 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-lxd/terraform-provider-lxd/internal/common"
 	"github.com/terraform-lxd/terraform-provider-lxd/internal/errors"
 	provider_config "github.com/terraform-lxd/terraform-provider-lxd/internal/provider-config"
 )
 
 type InstanceSnapshotModel struct {
-	Name     types.String `tfsdk:"name"`
-	Instance types.String `tfsdk:"instance"`
-	Stateful types.Bool   `tfsdk:"stateful"`
-	Project  types.String `tfsdk:"project"`
-	Remote   types.String `tfsdk:"remote"`
+	Name           types.String `tfsdk:"name"`
+	Instance       types.String `tfsdk:"instance"`
+	Stateful       types.Bool   `tfsdk:"stateful"`
+	ExpiresAt      types.String `tfsdk:"expires_at"`
+	RestoreTrigger types.String `tfsdk:"restore_trigger"`
+	Project        types.String `tfsdk:"project"`
+	Remote         types.String `tfsdk:"remote"`
 
 	// Computed.
 	CreatedAt types.Int64 `tfsdk:"created_at"`
@@ -77,6 +80,23 @@ func (r InstanceSnapshotResource) Schema(_ context.Context, _ resource.SchemaReq
 				},
 			},
 
+			"expires_at": schema.StringAttribute{
+				Description: "Expiry date of the snapshot in RFC 3339 format.",
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+				Validators: []validator.String{
+					common.TimestampValidator{},
+				},
+			},
+
+			"restore_trigger": schema.StringAttribute{
+				Description: "Restore the instance from the snapshot whenever this value changes.",
+				Optional:    true,
+			},
+
 			"project": schema.StringAttribute{
 				Optional: true,
 				Computed: true,
@@ -143,6 +163,17 @@ func (r InstanceSnapshotResource) Create(ctx context.Context, req resource.Creat
 		Stateful: plan.Stateful.ValueBool(),
 	}
 
+	if plan.ExpiresAt.ValueString() != "" {
+		// Timestamp is validated beforehand.
+		expiresAt, err := time.Parse(time.RFC3339, plan.ExpiresAt.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to parse expiry date of snapshot %q", snapshotName), err.Error())
+			return
+		}
+
+		snapshotReq.ExpiresAt = &expiresAt
+	}
+
 	var serr error
 	for i := range 5 {
 		op, err := server.CreateInstanceSnapshot(instanceName, snapshotReq)
@@ -182,6 +213,10 @@ func (r InstanceSnapshotResource) Create(ctx context.Context, req resource.Creat
 		return
 	}
 
+	configured, diags := expiresAtConfigured(ctx, req.Config)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, snapshotExpiresAtKey, configured)...)
+
 	// Update Terraform state.
 	diags = r.SyncState(ctx, &resp.State, server, plan)
 	resp.Diagnostics.Append(diags...)
@@ -210,7 +245,108 @@ func (r InstanceSnapshotResource) Read(ctx context.Context, req resource.ReadReq
 	resp.Diagnostics.Append(diags...)
 }
 
+// Update updates the snapshot expiry date and restores the instance
+// from the snapshot if the restore trigger has changed.
 func (r InstanceSnapshotResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan InstanceSnapshotModel
+	var state InstanceSnapshotModel
+
+	// Fetch resource model from Terraform plan and state.
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	remote := plan.Remote.ValueString()
+	project := plan.Project.ValueString()
+	server, err := r.provider.InstanceServer(remote, project, "")
+	if err != nil {
+		resp.Diagnostics.Append(errors.NewInstanceServerError(err))
+		return
+	}
+
+	instanceName := plan.Instance.ValueString()
+	snapshotName := plan.Name.ValueString()
+
+	snapshot, etag, err := server.GetInstanceSnapshot(instanceName, snapshotName)
+	if err != nil {
+		resp.Diagnostics.AddError(fmt.Sprintf("Failed to retrieve snapshot %q for instance %q", snapshotName, instanceName), err.Error())
+		return
+	}
+
+	// Update snapshot expiry date.
+	if !plan.ExpiresAt.Equal(state.ExpiresAt) {
+		newSnapshot := snapshot.Writable()
+		newSnapshot.ExpiresAt = time.Time{}
+
+		if plan.ExpiresAt.ValueString() != "" {
+			newSnapshot.ExpiresAt, err = time.Parse(time.RFC3339, plan.ExpiresAt.ValueString())
+			if err != nil {
+				resp.Diagnostics.AddError(fmt.Sprintf("Failed to parse expiry date of snapshot %q", snapshotName), err.Error())
+				return
+			}
+		}
+
+		op, err := server.UpdateInstanceSnapshot(instanceName, snapshotName, newSnapshot, etag)
+		if err == nil {
+			err = op.WaitContext(ctx)
+		}
+
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to update snapshot %q for instance %q", snapshotName, instanceName), err.Error())
+			return
+		}
+	}
+
+	// Restore the instance from the snapshot.
+	if !plan.RestoreTrigger.IsNull() && !plan.RestoreTrigger.Equal(state.RestoreTrigger) {
+		restoreReq := api.InstancePut{
+			Restore:  snapshotName,
+			Stateful: snapshot.Stateful,
+		}
+
+		op, err := server.UpdateInstance(instanceName, restoreReq, "")
+		if err == nil {
+			err = op.WaitContext(ctx)
+		}
+
+		if err != nil {
+			resp.Diagnostics.AddError(fmt.Sprintf("Failed to restore instance %q from snapshot %q", instanceName, snapshotName), err.Error())
+			return
+		}
+	}
+
+	configured, diags := expiresAtConfigured(ctx, req.Config)
+	resp.Diagnostics.Append(diags...)
+	resp.Diagnostics.Append(resp.Private.SetKey(ctx, snapshotExpiresAtKey, configured)...)
+
+	// Update Terraform state.
+	diags = r.SyncState(ctx, &resp.State, server, plan)
+	resp.Diagnostics.Append(diags...)
+}
+
+// ModifyPlan clears the snapshot expiry date once it is removed from the
+// configuration. The expiry date assigned by LXD (e.g. from the instance's
+// "snapshots.expiry" config) is otherwise retained.
+func (r InstanceSnapshotResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var expiresAt types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("expires_at"), &expiresAt)...)
+	if resp.Diagnostics.HasError() || !expiresAt.IsNull() {
+		return
+	}
+
+	configured, diags := req.Private.GetKey(ctx, snapshotExpiresAtKey)
+	resp.Diagnostics.Append(diags...)
+	if len(configured) == 0 {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("expires_at"), types.StringNull())...)
 }
 
 func (r InstanceSnapshotResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
@@ -244,6 +380,27 @@ func (r InstanceSnapshotResource) Delete(ctx context.Context, req resource.Delet
 	}
 }
 
+func (r InstanceSnapshotResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	meta := common.ImportMetadata{
+		ResourceName:   "snapshot",
+		RequiredFields: []string{"instance", "name"},
+	}
+
+	fields, diag := meta.ParseImportID(req.ID)
+	if diag != nil {
+		resp.Diagnostics.Append(diag)
+		return
+	}
+
+	if fields["project"] == "" {
+		fields["project"] = provider_config.DefaultProject
+	}
+
+	for k, v := range fields {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root(k), v)...)
+	}
+}
+
 // SyncState fetches the server's current state for an instance snapshot and
 // updates the provided model. It then applies this updated model as the new
 // state in Terraform.
@@ -266,5 +423,32 @@ func (r InstanceSnapshotResource) SyncState(ctx context.Context, tfState *tfsdk.
 	m.Stateful = types.BoolValue(snapshot.Stateful)
 	m.CreatedAt = types.Int64Value(snapshot.CreatedAt.Unix())
 
+	if snapshot.ExpiresAt.IsZero() {
+		m.ExpiresAt = types.StringNull()
+	} else {
+		// Keep the configured timestamp if it represents the same
+		// point in time to avoid diffs caused by formatting.
+		expiresAt, err := time.Parse(time.RFC3339, m.ExpiresAt.ValueString())
+		if err != nil || !expiresAt.Equal(snapshot.ExpiresAt) {
+			m.ExpiresAt = types.StringValue(snapshot.ExpiresAt.UTC().Format(time.RFC3339))
+		}
+	}
+
 	return tfState.Set(ctx, &m)
 }
+
+// snapshotExpiresAtKey is the private state key that records whether the
+// snapshot expiry date is set in the configuration.
+const snapshotExpiresAtKey = "expires_at_configured"
+
+// expiresAtConfigured returns the private state value that records whether
+// the snapshot expiry date is set in the given configuration.
+func expiresAtConfigured(ctx context.Context, config tfsdk.Config) ([]byte, diag.Diagnostics) {
+	var expiresAt types.String
+	diags := config.GetAttribute(ctx, path.Root("expires_at"), &expiresAt)
+	if diags.HasError() || expiresAt.IsNull() {
+		return nil, diags
+	}
+
+	return []byte("true"), diags
+}
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
 	"github.com/terraform-lxd/terraform-provider-lxd/internal/acctest"
 )
 
@@ -133,6 +134,113 @@ func TestAccInstanceSnapshot_missingInstance(t *testing.T) {
 	})
 }
 
+func TestAccInstanceSnapshot_expiresAt(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+	snapshotName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceSnapshot_expiresAt(instanceName, snapshotName, "2100-01-01T00:00:00Z"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_snapshot.snapshot1", "name", snapshotName),
+					resource.TestCheckResourceAttr("lxd_snapshot.snapshot1", "expires_at", "2100-01-01T00:00:00Z"),
+					resource.TestCheckResourceAttrSet("lxd_snapshot.snapshot1", "created_at"),
+				),
+			},
+			{
+				// Ensure expiry date is updated in place.
+				Config: acctest.Provider() + testAccInstanceSnapshot_expiresAt(instanceName, snapshotName, "2100-06-01T12:00:00+02:00"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("lxd_snapshot.snapshot1", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_snapshot.snapshot1", "name", snapshotName),
+					resource.TestCheckResourceAttr("lxd_snapshot.snapshot1", "expires_at", "2100-06-01T12:00:00+02:00"),
+				),
+			},
+			{
+				// Ensure expiry date is cleared once removed from the config.
+				Config: acctest.Provider() + testAccInstanceSnapshot_basic(instanceName, snapshotName, false),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("lxd_snapshot.snapshot1", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_snapshot.snapshot1", "name", snapshotName),
+					resource.TestCheckNoResourceAttr("lxd_snapshot.snapshot1", "expires_at"),
+				),
+			},
+			{
+				Config:      acctest.Provider() + testAccInstanceSnapshot_expiresAt(instanceName, snapshotName, "tomorrow"),
+				ExpectError: regexp.MustCompile("Invalid timestamp"),
+			},
+		},
+	})
+}
+
+func TestAccInstanceSnapshot_restoreTrigger(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+	snapshotName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceSnapshot_restoreTrigger(instanceName, snapshotName, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_snapshot.snapshot1", "name", snapshotName),
+					resource.TestCheckResourceAttr("lxd_snapshot.snapshot1", "restore_trigger", "1"),
+				),
+			},
+			{
+				// Ensure the instance is restored without replacing the snapshot.
+				Config: acctest.Provider() + testAccInstanceSnapshot_restoreTrigger(instanceName, snapshotName, "2"),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("lxd_snapshot.snapshot1", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_snapshot.snapshot1", "name", snapshotName),
+					resource.TestCheckResourceAttr("lxd_snapshot.snapshot1", "restore_trigger", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstanceSnapshot_importBasic(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+	snapshotName := acctest.GenerateName(2, "-")
+	resourceName := "lxd_snapshot.snapshot1"
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstanceSnapshot_basic(instanceName, snapshotName, false),
+			},
+			{
+				ResourceName:                         resourceName,
+				ImportStateId:                        fmt.Sprintf("/%s/%s", instanceName, snapshotName),
+				ImportStateVerifyIdentifierAttribute: "name",
+				ImportState:                          true,
+				ImportStateVerify:                    true,
+			},
+		},
+	})
+}
+
 func testAccInstanceSnapshot_basic(instanceName, snapshotName string, stateful bool) string {
 	return fmt.Sprintf(`
 resource "lxd_instance" "instance1" {
@@ -217,3 +325,33 @@ resource "lxd_snapshot" "snapshot1" {
 }
 	`, instanceName, snapshotName)
 }
+
+func testAccInstanceSnapshot_expiresAt(instanceName, snapshotName string, expiresAt string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+}
+
+resource "lxd_snapshot" "snapshot1" {
+  instance   = lxd_instance.instance1.name
+  name       = "%s"
+  expires_at = "%s"
+}
+	`, instanceName, acctest.TestImage, snapshotName, expiresAt)
+}
+
+func testAccInstanceSnapshot_restoreTrigger(instanceName, snapshotName string, trigger string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+}
+
+resource "lxd_snapshot" "snapshot1" {
+  instance        = lxd_instance.instance1.name
+  name            = "%s"
+  restore_trigger = "%s"
+}
+	`, instanceName, acctest.TestImage, snapshotName, trigger)
+}
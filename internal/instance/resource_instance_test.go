@@ -3,6 +3,7 @@ package instance_test
 import (
 	"fmt"
 	"regexp"
+	"runtime"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
@@ -229,6 +230,147 @@ func TestAccInstance_restartVirtualMachine(t *testing.T) {
 	})
 }
 
+func TestAccInstance_state(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_state(instanceName, "running"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "state", "running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "running", "true"),
+				),
+			},
+			{
+				Config: acctest.Provider() + testAccInstance_state(instanceName, "frozen"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Frozen"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "state", "frozen"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "running", "false"),
+				),
+			},
+			{
+				Config: acctest.Provider() + testAccInstance_state(instanceName, "stopped"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Stopped"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "state", "stopped"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "running", "false"),
+				),
+			},
+			{
+				// Ensure "running" attribute is reflected in "state".
+				Config: acctest.Provider() + testAccInstance_started(instanceName, "container"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "state", "running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "running", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_stateConflict(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.Provider() + testAccInstance_stateConflict(instanceName),
+				ExpectError: regexp.MustCompile(`Attributes "running" and "state" cannot be set at the same time`),
+			},
+		},
+	})
+}
+
+func TestAccInstance_statefulStop(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			acctest.PreCheck(t)
+			acctest.PreCheckVirtualization(t)
+		},
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_statefulStop(instanceName, "running"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "stateful_stop", "true"),
+				),
+			},
+			{
+				Config: acctest.Provider() + testAccInstance_statefulStop(instanceName, "stopped"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Stopped"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "state", "stopped"),
+				),
+			},
+			{
+				Config: acctest.Provider() + testAccInstance_statefulStop(instanceName, "running"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "state", "running"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_stopTimeout(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_stopTimeout(instanceName, "running", "30s", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "stop_timeout", "30s"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "force_stop_on_delete", "false"),
+				),
+			},
+			{
+				Config: acctest.Provider() + testAccInstance_stopTimeout(instanceName, "stopped", "30s", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Stopped"),
+				),
+			},
+			{
+				Config: acctest.Provider() + testAccInstance_stopTimeout(instanceName, "running", "1s", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "stop_timeout", "1s"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "force_stop_on_delete", "true"),
+				),
+			},
+			{
+				Config:      acctest.Provider() + testAccInstance_stopTimeout(instanceName, "running", "invalid", true),
+				ExpectError: regexp.MustCompile(`Invalid duration`),
+			},
+		},
+	})
+}
+
 func TestAccInstance_renameInstance(t *testing.T) {
 	instanceNameA := acctest.GenerateName(3, "-")
 	instanceNameB := acctest.GenerateName(3, "-")
@@ -352,6 +494,29 @@ func TestAccInstance_remoteImage(t *testing.T) {
 	})
 }
 
+func TestAccInstance_architecture(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.Provider() + testAccInstance_architecture(instanceName, "s390x"),
+				ExpectError: regexp.MustCompile(`Architecture "s390x" is not supported by the server`),
+			},
+			{
+				Config: acctest.Provider() + testAccInstance_architecture(instanceName, testInstanceArchitecture()),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Stopped"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "architecture", testInstanceArchitecture()),
+				),
+			},
+		},
+	})
+}
+
 func TestAccInstance_config(t *testing.T) {
 	instanceName := acctest.GenerateName(2, "-")
 
@@ -411,6 +576,89 @@ func TestAccInstance_updateConfig(t *testing.T) {
 	})
 }
 
+func TestAccInstance_snapshotBeforeUpdate(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_snapshotBeforeUpdate(instanceName, "1"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "config.user.dummy", "1"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "snapshot_before_update.enabled", "true"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "snapshot_before_update.keep", "2"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "snapshot_before_update.stateful", "false"),
+				),
+			},
+			{
+				Config: acctest.Provider() + testAccInstance_snapshotBeforeUpdate(instanceName, "2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "config.user.dummy", "2"),
+				),
+			},
+			{
+				// Ensure the instance is restored when the update fails.
+				Config:      acctest.Provider() + testAccInstance_snapshotBeforeUpdateFailure(instanceName),
+				ExpectError: regexp.MustCompile(`Failed to execute command`),
+			},
+			{
+				// Refresh the state without applying the configuration
+				// to verify the instance config has been restored.
+				RefreshState:       true,
+				ExpectNonEmptyPlan: true,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "config.user.dummy", "2"),
+				),
+			},
+			{
+				Config: acctest.Provider() + testAccInstance_snapshotBeforeUpdate(instanceName, "2"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "config.user.dummy", "2"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_snapshotBeforeUpdateFrozen(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_snapshotBeforeUpdateFrozen(instanceName, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Frozen"),
+				),
+			},
+			{
+				// Ensure the frozen instance is frozen again once restored.
+				Config:      acctest.Provider() + testAccInstance_snapshotBeforeUpdateFrozen(instanceName, true),
+				ExpectError: regexp.MustCompile(`Failed to execute command`),
+			},
+			{
+				RefreshState:       true,
+				ExpectNonEmptyPlan: true,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Frozen"),
+					resource.TestCheckNoResourceAttr("lxd_instance.instance1", "config.user.dummy"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccInstance_addProfile(t *testing.T) {
 	profileName := acctest.GenerateName(2, "-")
 	instanceName := acctest.GenerateName(2, "-")
@@ -652,6 +900,29 @@ func TestAccInstance_fileUploadVirtualMachine(t *testing.T) {
 	})
 }
 
+func TestAccInstance_fileUploadTypes(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_fileUploadTypes(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "file.#", "3"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.dir", "type", "directory"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.dir", "mode", "0750"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.file", "content", "Hello, World!\n"),
+					resource.TestCheckResourceAttr("data.lxd_instance_file.link", "type", "symlink"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccInstance_fileUploadContent(t *testing.T) {
 	instanceName := acctest.GenerateName(2, "-")
 
@@ -779,6 +1050,89 @@ func TestAccInstance_execOutputDate(t *testing.T) {
 	})
 }
 
+func TestAccInstance_execStdin(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_execStdin(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.cmd.stdin", "Hello, World!\n"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.cmd.stream_output", "true"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.cmd.exit_code", "0"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.cmd.stdout", "Hello, World!\n"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_execTimeout(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.Provider() + testAccInstance_execTimeout(instanceName, "1 minute"),
+				ExpectError: regexp.MustCompile(`Invalid duration`),
+			},
+			{
+				Config:      acctest.Provider() + testAccInstance_execTimeout(instanceName, "2s"),
+				ExpectError: regexp.MustCompile(`Command did not complete within 2s`),
+			},
+		},
+	})
+}
+
+func TestAccInstance_execTimeoutKill(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_execTimeoutKill(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.cmd.run_count", "1"),
+					// Timed out command must not be running anymore.
+					resource.TestCheckResourceAttr("lxd_instance_exec.check", "exit_code", "1"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_execRetries(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Command fails on the first attempt and succeeds on retry.
+				Config: acctest.Provider() + testAccInstance_execRetries(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.cmd.retries", "2"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.cmd.retry_interval", "1s"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.cmd.exit_code", "0"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.cmd.run_count", "1"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccInstance_execTriggerOnce(t *testing.T) {
 	instanceName := acctest.GenerateName(2, "-")
 
@@ -1204,6 +1558,38 @@ func TestAccInstance_vm_configLimits(t *testing.T) {
 	})
 }
 
+func TestAccInstance_restartRequired(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_restartRequired(instanceName, "false", false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "config.security.privileged", "false"),
+				),
+			},
+			{
+				// Changing "security.privileged" requires restart, which is not allowed.
+				Config:      acctest.Provider() + testAccInstance_restartRequired(instanceName, "true", false),
+				ExpectError: regexp.MustCompile(`(?s)Changes to the following keys cannot be applied to the running\s+instance: security.privileged`),
+			},
+			{
+				Config: acctest.Provider() + testAccInstance_restartRequired(instanceName, "true", true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "config.security.privileged", "true"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccInstance_accessInterface(t *testing.T) {
 	networkName := acctest.GenerateName(2, "-")
 	instanceName := acctest.GenerateName(2, "-")
@@ -1631,15 +2017,172 @@ func TestAccInstance_waitForIPv4AndIPv6(t *testing.T) {
 		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
 		Steps: []resource.TestStep{
 			{
-				Config: acctest.Provider() + testAccInstance_waitForIPv4AndIPv6(networkName, instanceName),
-				Check: resource.ComposeTestCheckFunc(
-					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
-					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
-					resource.TestCheckResourceAttr("lxd_instance.instance1", "wait_for.0.type", "ipv4"),
-					resource.TestCheckResourceAttr("lxd_instance.instance1", "wait_for.0.nic", "eth0"),
-					resource.TestCheckResourceAttr("lxd_instance.instance1", "wait_for.1.type", "ipv6"),
-					resource.TestCheckResourceAttr("lxd_instance.instance1", "wait_for.1.nic", "eth0"),
-				),
+				Config: acctest.Provider() + testAccInstance_waitForIPv4AndIPv6(networkName, instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "wait_for.0.type", "ipv4"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "wait_for.0.nic", "eth0"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "wait_for.1.type", "ipv6"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "wait_for.1.nic", "eth0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_waitForCloudInit(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_waitForCloudInit(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "wait_for.0.type", "cloud-init"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "execs.check.exit_code", "0"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_waitForExec(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_waitForExec(instanceName, `["test", "-f", "/etc/hostname"]`),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "wait_for.0.type", "exec"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "wait_for.0.command.#", "3"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "wait_for.0.interval", "1s"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "wait_for.0.retries", "3"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_waitForExecFailure(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.Provider() + testAccInstance_waitForExec(instanceName, `["false"]`),
+				ExpectError: regexp.MustCompile(`Failed to wait for command to succeed`),
+			},
+		},
+	})
+}
+
+func TestAccInstance_waitForFile(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_waitForFile(instanceName, "/etc/os-release"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "wait_for.0.type", "file"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "wait_for.0.path", "/etc/os-release"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_waitForFileDirectory(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				// Directories are returned without content.
+				Config: acctest.Provider() + testAccInstance_waitForFile(instanceName, "/etc"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "wait_for.0.type", "file"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "wait_for.0.path", "/etc"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_waitForPort(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: acctest.Provider() + testAccInstance_waitForPort(instanceName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "name", instanceName),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "status", "Running"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "wait_for.0.type", "port"),
+					resource.TestCheckResourceAttr("lxd_instance.instance1", "wait_for.0.port", "8080"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccInstance_waitForInvalid(t *testing.T) {
+	instanceName := acctest.GenerateName(2, "-")
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:                 func() { acctest.PreCheck(t) },
+		ProtoV6ProviderFactories: acctest.ProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config:      acctest.Provider() + testAccInstance_waitForInvalid(instanceName, `type = "exec"`),
+				ExpectError: regexp.MustCompile(`The "command" attribute is required when wait_for type is "exec"`),
+			},
+			{
+				Config:      acctest.Provider() + testAccInstance_waitForInvalid(instanceName, `type = "exec"`+"\n"+`command = ["true"]`+"\n"+`interval = "1 second"`),
+				ExpectError: regexp.MustCompile(`Invalid duration`),
+			},
+			{
+				Config:      acctest.Provider() + testAccInstance_waitForInvalid(instanceName, `type = "delay"`+"\n"+`delay = "1 second"`),
+				ExpectError: regexp.MustCompile(`Invalid duration`),
+			},
+			{
+				Config:      acctest.Provider() + testAccInstance_waitForInvalid(instanceName, `type = "file"`),
+				ExpectError: regexp.MustCompile(`The "path" attribute is required when wait_for type is "file"`),
+			},
+			{
+				Config:      acctest.Provider() + testAccInstance_waitForInvalid(instanceName, `type = "file"`+"\n"+`path = "etc/hostname"`),
+				ExpectError: regexp.MustCompile(`must be absolute`),
+			},
+			{
+				Config:      acctest.Provider() + testAccInstance_waitForInvalid(instanceName, `type = "port"`),
+				ExpectError: regexp.MustCompile(`The "port" attribute is required when wait_for type is "port"`),
+			},
+			{
+				Config:      acctest.Provider() + testAccInstance_waitForInvalid(instanceName, `type = "ready"`+"\n"+`retries = 3`),
+				ExpectError: regexp.MustCompile(`can only be set when wait_for type is "exec"`),
 			},
 		},
 	})
@@ -1725,6 +2268,74 @@ resource "lxd_instance" "instance1" {
 	`, name, acctest.TestImage, instanceType, config, waitForAgentConfig)
 }
 
+func testAccInstance_state(name string, state string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+  state = "%s"
+}
+	`, name, acctest.TestImage, state)
+}
+
+func testAccInstance_stateConflict(name string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name    = "%s"
+  image   = "%s"
+  running = true
+  state   = "running"
+}
+	`, name, acctest.TestImage)
+}
+
+func testAccInstance_statefulStop(name string, state string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name          = "%s"
+  image         = "%s"
+  type          = "virtual-machine"
+  state         = "%s"
+  stateful_stop = true
+
+  config = {
+    %s
+    "migration.stateful" = true
+  }
+
+  wait_for {
+    type = "agent"
+  }
+}
+	`, name, acctest.TestImage, state, acctest.DisableSecureBootConfigEntry())
+}
+
+func testAccInstance_stopTimeout(name string, state string, stopTimeout string, forceStopOnDelete bool) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name                 = "%s"
+  image                = "%s"
+  state                = "%s"
+  stop_timeout         = "%s"
+  force_stop_on_delete = %t
+}
+	`, name, acctest.TestImage, state, stopTimeout, forceStopOnDelete)
+}
+
+func testAccInstance_restartRequired(name string, privileged string, allowRestart bool) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name          = "%s"
+  image         = "%s"
+  allow_restart = %t
+
+  config = {
+    "security.privileged" = %s
+  }
+}
+	`, name, acctest.TestImage, allowRestart, privileged)
+}
+
 func testAccInstance_empty(name string, instanceType string) string {
 	var config string
 	if instanceType == "virtual-machine" {
@@ -1816,6 +2427,77 @@ resource "lxd_instance" "instance1" {
 	`, name)
 }
 
+func testAccInstance_snapshotBeforeUpdate(name string, dummy string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  config = {
+    "user.dummy" = "%s"
+  }
+
+  snapshot_before_update {
+    keep = 2
+  }
+}
+	`, name, acctest.TestImage, dummy)
+}
+
+func testAccInstance_snapshotBeforeUpdateFailure(name string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  config = {
+    "user.dummy" = "3"
+  }
+
+  snapshot_before_update {
+    keep = 2
+  }
+
+  execs = {
+    "fail" = {
+      command       = ["false"]
+      fail_on_error = true
+    }
+  }
+}
+	`, name, acctest.TestImage)
+}
+
+func testAccInstance_snapshotBeforeUpdateFrozen(name string, fail bool) string {
+	update := ""
+	if fail {
+		update = `
+  config = {
+    "user.dummy" = "1"
+  }
+
+  execs = {
+    "fail" = {
+      command       = ["false"]
+      fail_on_error = true
+    }
+  }`
+	}
+
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+  state = "frozen"
+  %s
+
+  snapshot_before_update {
+    keep = 2
+  }
+}
+	`, name, acctest.TestImage, update)
+}
+
 func testAccInstance_addProfile_1(instanceName string) string {
 	return fmt.Sprintf(`
 resource "lxd_instance" "instance1" {
@@ -2029,6 +2711,48 @@ resource "lxd_instance" "instance1" {
 	`, name, acctest.TestImage)
 }
 
+func testAccInstance_fileUploadTypes(name string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  file {
+    type        = "directory"
+    target_path = "/opt/app"
+    mode        = "0750"
+  }
+
+  file {
+    content     = "Hello, World!\n"
+    target_path = "/opt/app/hello.txt"
+    mode        = "0644"
+  }
+
+  file {
+    type           = "symlink"
+    symlink_target = "/opt/app/hello.txt"
+    target_path    = "/opt/hello.txt"
+  }
+}
+
+data "lxd_instance_file" "dir" {
+  instance = lxd_instance.instance1.name
+  path     = "/opt/app"
+}
+
+data "lxd_instance_file" "file" {
+  instance = lxd_instance.instance1.name
+  path     = "/opt/app/hello.txt"
+}
+
+data "lxd_instance_file" "link" {
+  instance = lxd_instance.instance1.name
+  path     = "/opt/hello.txt"
+}
+	`, name, acctest.TestImage)
+}
+
 func testAccInstance_fileUploadSource(instanceName string, instanceType string) string {
 	var config string
 	if instanceType == "virtual-machine" {
@@ -2095,6 +2819,80 @@ resource "lxd_instance" "instance1" {
 	`, instanceName, acctest.TestImage)
 }
 
+func testAccInstance_execStdin(instanceName string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  execs = {
+    "cmd" = {
+      command       = ["cat"]
+      stdin         = "Hello, World!\n"
+      record_output = true
+      stream_output = true
+    }
+  }
+}
+	`, instanceName, acctest.TestImage)
+}
+
+func testAccInstance_execTimeout(instanceName string, timeout string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  execs = {
+    "cmd" = {
+      command       = ["sleep", "60"]
+      timeout       = "%s"
+      fail_on_error = true
+    }
+  }
+}
+	`, instanceName, acctest.TestImage, timeout)
+}
+
+func testAccInstance_execTimeoutKill(instanceName string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  execs = {
+    "cmd" = {
+      command = ["sleep", "600"]
+      timeout = "2s"
+    }
+  }
+}
+
+resource "lxd_instance_exec" "check" {
+  instance = lxd_instance.instance1.name
+  command  = ["pgrep", "-x", "sleep"]
+}
+	`, instanceName, acctest.TestImage)
+}
+
+func testAccInstance_execRetries(instanceName string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  execs = {
+    "cmd" = {
+      command        = ["/bin/sh", "-c", "test -f /tmp/retry || { touch /tmp/retry; exit 1; }"]
+      retries        = 2
+      retry_interval = "1s"
+      fail_on_error  = true
+    }
+  }
+}
+	`, instanceName, acctest.TestImage)
+}
+
 func testAccInstance_execOutputDate(instanceName string) string {
 	return fmt.Sprintf(`
 resource "lxd_instance" "instance1" {
@@ -2329,6 +3127,28 @@ resource "lxd_instance" "instance1" {
 	`, name, acctest.TestImage)
 }
 
+func testAccInstance_architecture(name string, architecture string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name         = "%s"
+  image        = "%s"
+  architecture = "%s"
+  running      = false
+}
+	`, name, acctest.TestImage, architecture)
+}
+
+// testInstanceArchitecture returns the LXD architecture name of the
+// machine running the tests.
+func testInstanceArchitecture() string {
+	switch runtime.GOARCH {
+	case "arm64":
+		return "aarch64"
+	default:
+		return "x86_64"
+	}
+}
+
 func testAccInstance_configLimits_1(name string) string {
 	return fmt.Sprintf(`
 resource "lxd_instance" "instance1" {
@@ -2631,6 +3451,100 @@ resource "lxd_instance" "instance1" {
 	`, name, acctest.TestImage, delay)
 }
 
+func testAccInstance_waitForCloudInit(name string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s/cloud"
+
+  config = {
+    "cloud-init.user-data" = <<-EOF
+      #cloud-config
+      write_files:
+        - path: /tmp/cloud-init-done
+          content: done
+    EOF
+  }
+
+  wait_for {
+    type = "cloud-init"
+  }
+
+  execs = {
+    "check" = {
+      command       = ["test", "-f", "/tmp/cloud-init-done"]
+      fail_on_error = true
+    }
+  }
+}
+	`, name, acctest.TestImage)
+}
+
+func testAccInstance_waitForExec(name string, command string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  wait_for {
+    type     = "exec"
+    command  = %s
+    interval = "1s"
+    retries  = 3
+  }
+}
+	`, name, acctest.TestImage, command)
+}
+
+func testAccInstance_waitForFile(name string, filePath string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  wait_for {
+    type = "file"
+    path = "%s"
+  }
+}
+	`, name, acctest.TestImage, filePath)
+}
+
+func testAccInstance_waitForPort(name string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s/cloud"
+
+  config = {
+    "cloud-init.user-data" = <<-EOF
+      #cloud-config
+      runcmd:
+        - [sh, -c, "while true; do nc -l -p 8080; done > /dev/null 2>&1 &"]
+    EOF
+  }
+
+  wait_for {
+    type = "port"
+    port = 8080
+  }
+}
+	`, name, acctest.TestImage)
+}
+
+func testAccInstance_waitForInvalid(name string, waitFor string) string {
+	return fmt.Sprintf(`
+resource "lxd_instance" "instance1" {
+  name  = "%s"
+  image = "%s"
+
+  wait_for {
+    %s
+  }
+}
+	`, name, acctest.TestImage, waitFor)
+}
+
 func testAccInstance_waitForIPv4(networkName, instanceName string) string {
 	return fmt.Sprintf(`
 resource "lxd_network" "network1" {
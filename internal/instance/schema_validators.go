@@ -3,6 +3,7 @@ package instance
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
 	"github.com/terraform-lxd/terraform-provider-lxd/internal/utils"
@@ -28,3 +29,29 @@ func (v configKeyValidator) ValidateString(ctx context.Context, req validator.St
 		)
 	}
 }
+
+// durationValidator ensures value is a valid duration, such as "30s" or "5m".
+type durationValidator struct{}
+
+func (v durationValidator) Description(ctx context.Context) string {
+	return "value must be a valid duration"
+}
+
+func (v durationValidator) MarkdownDescription(ctx context.Context) string {
+	return "value must be a valid duration"
+}
+
+func (v durationValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	value := req.ConfigValue.ValueString()
+
+	_, err := time.ParseDuration(value)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid duration",
+			fmt.Sprintf("Value must be a valid duration, e.g. %q or %q. Got: %q.", "30s", "5m", value),
+		)
+	}
+}
@@ -315,7 +315,12 @@ func (p *LxdProvider) Resources(_ context.Context) []func() resource.Resource {
 		auth.NewAuthIdentityResource,
 		image.NewCachedImageResource,
 		image.NewPublishImageResource,
+		image.NewImageResource,
+		image.NewImageExportResource,
+		image.NewImageAliasResource,
 		instance.NewInstanceResource,
+		instance.NewInstanceExecResource,
+		instance.NewInstanceDirectoryResource,
 		instance.NewInstanceFileResource,
 		instance.NewInstanceSnapshotResource,
 		instance.NewInstanceDeviceResource,
@@ -350,7 +355,12 @@ func (p *LxdProvider) DataSources(_ context.Context) []func() datasource.DataSou
 		auth.NewAuthGroupDataSource,
 		auth.NewAuthIdentityDataSource,
 		image.NewImageDataSource,
+		image.NewImagesDataSource,
 		instance.NewInstanceDataSource,
+		instance.NewInstanceFileDataSource,
+		instance.NewInstanceConsoleLogDataSource,
+		instance.NewInstanceStateDataSource,
+		instance.NewInstancesDataSource,
 		network.NewNetworkDataSource,
 		profile.NewProfileDataSource,
 		project.NewProjectDataSource,